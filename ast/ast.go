@@ -0,0 +1,96 @@
+// Package ast declares the types used to represent syntax trees of
+// extended JSON documents, which can be used to build linters and other
+// tools for extended JSON files.
+package ast
+
+import (
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// Position describes a location in the source data.
+type Position = parser.Position
+
+// Node is implemented by all node types.
+type Node interface {
+	Pos() Position // position of the first character belonging to the node
+	End() Position // position of the first character immediately after the node
+}
+
+// Span is the source range of a node, it is embedded in all node types.
+type Span struct {
+	Start Position
+	Stop  Position
+}
+
+// Pos returns position of the first character belonging to the node.
+func (s Span) Pos() Position { return s.Start }
+
+// End returns position of the first character immediately after the node.
+func (s Span) End() Position { return s.Stop }
+
+// File represents a parsed extended JSON document.
+type File struct {
+	Span
+	Value    Node       // the root value
+	Comments []*Comment // all comments in source order
+}
+
+// Object represents an object, i.e. {...}.
+type Object struct {
+	Span
+	Members []*Member
+}
+
+// Member represents a key-value pair of an object.
+type Member struct {
+	Span
+	Key   *String // the key, Raw is the identifier for unquoted keys
+	Value Node
+}
+
+// Array represents an array [...], Python tuples and sets are also
+// represented as Array.
+type Array struct {
+	Span
+	Elems []Node
+}
+
+// String represents a string, including unquoted keys, quoteless and
+// multiline strings.
+type String struct {
+	Span
+	Raw   string // source text
+	Value string // decoded value
+}
+
+// Number represents a number.
+type Number struct {
+	Span
+	Raw string // source text
+}
+
+// Bool represents true or false, or Python True or False.
+type Bool struct {
+	Span
+	Raw   string // source text
+	Value bool
+}
+
+// Null represents null, or Python None.
+type Null struct {
+	Span
+	Raw string // source text
+}
+
+// Directive represents a directive, e.g. @env("HOME").
+type Directive struct {
+	Span
+	Name string  // name of the directive, "env", "incl", "ref" or "fn"
+	Arg  *String // the argument
+}
+
+// Comment represents a comment, i.e. // ..., /* ... */, or # ....
+type Comment struct {
+	Span
+	Text string // comment text, including the comment markers
+}
@@ -0,0 +1,95 @@
+package ast
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var testData = `{
+	// comment
+	name: 'extjson', # pragma
+	"list": [1, true, None, /* inline */ @env("HOME")],
+	"ünï": "côdé",
+}`
+
+func TestParse(t *testing.T) {
+	file, err := Parse([]byte(testData), 0)
+	if err != nil {
+		t.Fatalf("failed parse: %v", err)
+	}
+
+	var got []string
+	Inspect(file, func(node Node) bool {
+		switch n := node.(type) {
+		case *Member:
+			got = append(got, fmt.Sprintf("Member %v", n.Pos()))
+		case *String:
+			got = append(got, fmt.Sprintf("String %v %s %q", n.Pos(), n.Raw, n.Value))
+		case *Number:
+			got = append(got, fmt.Sprintf("Number %v %s", n.Pos(), n.Raw))
+		case *Bool:
+			got = append(got, fmt.Sprintf("Bool %v %v", n.Pos(), n.Value))
+		case *Null:
+			got = append(got, fmt.Sprintf("Null %v %s", n.Pos(), n.Raw))
+		case *Directive:
+			got = append(got, fmt.Sprintf("Directive %v %s", n.Pos(), n.Name))
+		case *Comment:
+			got = append(got, fmt.Sprintf("Comment %v-%v %s", n.Pos(), n.End(), n.Text))
+		case nil:
+		default:
+			got = append(got, fmt.Sprintf("%T %v-%v", n, n.Pos(), n.End()))
+		}
+		return true
+	})
+	want := []string{
+		"*ast.File 1:1-6:2",
+		"*ast.Object 1:1-6:2",
+		"Member 3:2",
+		`String 3:2 name "name"`,
+		`String 3:8 'extjson' "extjson"`,
+		"Member 4:2",
+		`String 4:2 "list" "list"`,
+		"*ast.Array 4:10-4:52",
+		"Number 4:11 1",
+		"Bool 4:14 true",
+		"Null 4:20 None",
+		"Directive 4:39 env",
+		`String 4:44 "HOME" "HOME"`,
+		"Member 5:2",
+		`String 5:2 "ünï" "ünï"`,
+		`String 5:9 "côdé" "côdé"`,
+		"Comment 2:2-2:12 // comment",
+		"Comment 3:19-3:27 # pragma",
+		"Comment 4:26-4:38 /* inline */",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	obj := file.Value.(*Object)
+	if pos := obj.Members[2].Value.Pos(); pos.Offset != strings.Index(testData, `"côdé"`) {
+		t.Errorf("incorrect byte offset: %v", pos.Offset)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	data := "{a: (1, 2), b: 0x10, c: '''\n  text\n  '''}"
+	if _, err := Parse([]byte(data), 0); err == nil {
+		t.Errorf("expecting error without grammar modes")
+	}
+	file, err := Parse([]byte(data), JSON5|Hjson|Python)
+	if err != nil {
+		t.Fatalf("failed parse: %v", err)
+	}
+	obj := file.Value.(*Object)
+	if arr, ok := obj.Members[0].Value.(*Array); !ok || len(arr.Elems) != 2 {
+		t.Errorf("expecting tuple as array, got %#v", obj.Members[0].Value)
+	}
+	if num := obj.Members[1].Value.(*Number); num.Raw != "0x10" {
+		t.Errorf("incorrect number: %v", num.Raw)
+	}
+	if str := obj.Members[2].Value.(*String); str.Value != "text" {
+		t.Errorf("incorrect multiline string: %q", str.Value)
+	}
+}
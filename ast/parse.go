@@ -0,0 +1,108 @@
+package ast
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// Mode enables optional grammar modes of Parse.
+type Mode uint
+
+const (
+	JSON5  Mode = 1 << iota // enable the JSON5 grammar
+	Hjson                   // enable the Hjson grammar
+	Python                  // enable Python literals
+)
+
+// Parse parses data and returns the syntax tree.
+// Directives are not evaluated.
+func Parse(data []byte, mode Mode) (*File, error) {
+	tree, err := parser.ParseTree(data, parser.Options{
+		JSON5:  mode&JSON5 != 0,
+		Hjson:  mode&Hjson != 0,
+		Python: mode&Python != 0,
+	})
+	if err != nil {
+		return nil, err
+	}
+	b := &builder{data: data, lines: []int{0}}
+	for i, c := range data {
+		if c == '\n' {
+			b.lines = append(b.lines, i+1)
+		}
+	}
+	file := &File{
+		Span:  b.span(0, len(data)),
+		Value: b.node(tree.Root),
+	}
+	for _, c := range tree.Comments {
+		file.Comments = append(file.Comments, &Comment{
+			Span: b.span(c.Begin, c.End),
+			Text: string(data[c.Begin:c.End]),
+		})
+	}
+	return file, nil
+}
+
+type builder struct {
+	data  []byte
+	lines []int // offsets of the beginning of lines
+}
+
+func (b *builder) position(offset int) Position {
+	line := sort.SearchInts(b.lines, offset+1) - 1
+	return Position{
+		Offset: offset,
+		Line:   line + 1,
+		Column: utf8.RuneCount(b.data[b.lines[line]:offset]) + 1,
+	}
+}
+
+func (b *builder) span(begin, end int) Span {
+	return Span{Start: b.position(begin), Stop: b.position(end)}
+}
+
+func (b *builder) node(n *parser.Node) Node {
+	span := b.span(n.Begin, n.End)
+	raw := string(b.data[n.Begin:n.End])
+	switch n.Kind {
+	case parser.NodeObject:
+		obj := &Object{Span: span}
+		for _, c := range n.Children {
+			obj.Members = append(obj.Members, &Member{
+				Span: b.span(c.KeyBegin, c.End),
+				Key: &String{
+					Span:  b.span(c.KeyBegin, c.KeyEnd),
+					Raw:   string(b.data[c.KeyBegin:c.KeyEnd]),
+					Value: c.Key,
+				},
+				Value: b.node(c),
+			})
+		}
+		return obj
+	case parser.NodeArray:
+		arr := &Array{Span: span}
+		for _, c := range n.Children {
+			arr.Elems = append(arr.Elems, b.node(c))
+		}
+		return arr
+	case parser.NodeString:
+		return &String{Span: span, Raw: raw, Value: n.Value}
+	case parser.NodeNumber:
+		return &Number{Span: span, Raw: raw}
+	case parser.NodeBool:
+		return &Bool{Span: span, Raw: raw, Value: raw == "true" || raw == "True"}
+	case parser.NodeNull:
+		return &Null{Span: span, Raw: raw}
+	case parser.NodeDirective:
+		return &Directive{
+			Span: span,
+			Name: raw[1:strings.IndexByte(raw, '(')],
+			Arg:  b.node(n.Children[0]).(*String),
+		}
+	}
+	return nil
+}
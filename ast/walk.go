@@ -0,0 +1,69 @@
+package ast
+
+import "fmt"
+
+// A Visitor's Visit method is invoked for each node encountered by Walk.
+// If the result visitor w is not nil, Walk visits each of the children
+// of node with the visitor w, followed by a call of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses a syntax tree in depth-first order: It starts by
+// calling v.Visit(node); node must not be nil. If the visitor w
+// returned by v.Visit(node) is not nil, Walk is invoked recursively
+// with visitor w for each of the non-nil children of node, followed by
+// a call of w.Visit(nil).
+//
+// Comments of a File are visited after the root value.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *File:
+		if n.Value != nil {
+			Walk(v, n.Value)
+		}
+		for _, c := range n.Comments {
+			Walk(v, c)
+		}
+	case *Object:
+		for _, m := range n.Members {
+			Walk(v, m)
+		}
+	case *Member:
+		Walk(v, n.Key)
+		Walk(v, n.Value)
+	case *Array:
+		for _, x := range n.Elems {
+			Walk(v, x)
+		}
+	case *Directive:
+		Walk(v, n.Arg)
+	case *String, *Number, *Bool, *Null, *Comment:
+		// nothing to do
+	default:
+		panic(fmt.Sprintf("ast.Walk: unexpected node type %T", n))
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses a syntax tree in depth-first order: It starts by
+// calling f(node); node must not be nil. If f returns true, Inspect
+// invokes f recursively for each of the non-nil children of node,
+// followed by a call of f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
@@ -0,0 +1,223 @@
+// Command extjson cleans, validates and queries extended JSON files.
+//
+// Usage:
+//
+//	extjson clean [flags] [file]
+//	extjson check [flags] [file ...]
+//	extjson get [flags] file path
+//
+// The clean command prints the file as strict JSON, the check command
+// reports errors of the files and exits with a nonzero status if any
+// file is invalid, the get command prints the value at a gjson path.
+// If file is "-" or absent, the data is read from the standard input.
+// Flags may be given before or after the operands, arguments after "--"
+// are not parsed as flags.
+//
+// Flags:
+//
+//	-env           enable reading environment variables
+//	-include-root  root directory of included files, default is the working directory
+//	-pretty        pretty print the output
+//	-json5         enable the JSON5 grammar
+//	-hjson         enable the Hjson grammar
+//	-python        enable Python literals
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/jxskiss/extjson"
+	"github.com/tidwall/gjson"
+)
+
+const usage = `Usage:
+	extjson clean [flags] [file]
+	extjson check [flags] [file ...]
+	extjson get [flags] file path
+
+Use "extjson <command> -h" for flags of the command.
+`
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs the command and returns the exit status.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return 2
+	}
+	cmd := &command{stdin: stdin, stdout: stdout, stderr: stderr}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.BoolVar(&cmd.env, "env", false, "enable reading environment variables")
+	flags.StringVar(&cmd.includeRoot, "include-root", "", "root directory of included files, default is the working directory")
+	flags.BoolVar(&cmd.pretty, "pretty", false, "pretty print the output")
+	flags.BoolVar(&cmd.json5, "json5", false, "enable the JSON5 grammar")
+	flags.BoolVar(&cmd.hjson, "hjson", false, "enable the Hjson grammar")
+	flags.BoolVar(&cmd.python, "python", false, "enable Python literals")
+	operands, err := parseArgs(flags, args[1:])
+	if err != nil {
+		return 2
+	}
+
+	switch args[0] {
+	case "clean":
+		if len(operands) > 1 {
+			return cmd.usage("clean accepts at most one file")
+		}
+		var name string
+		if len(operands) > 0 {
+			name = operands[0]
+		}
+		err = cmd.clean(name)
+	case "check":
+		return cmd.check(operands)
+	case "get":
+		if len(operands) != 2 {
+			return cmd.usage("get requires a file and a path")
+		}
+		err = cmd.get(operands[0], operands[1])
+	default:
+		return cmd.usage(fmt.Sprintf("unknown command %q", args[0]))
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "extjson: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseArgs parses the flags which may be mixed with the operands,
+// it returns the operands. Arguments after "--" are all operands.
+func parseArgs(flags *flag.FlagSet, args []string) ([]string, error) {
+	var operands []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := flags.Args()
+		if n := len(args) - len(rest); n > 0 && args[n-1] == "--" {
+			return append(operands, rest...), nil
+		}
+		if len(rest) == 0 {
+			return operands, nil
+		}
+		operands = append(operands, rest[0])
+		args = rest[1:]
+	}
+}
+
+type command struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+
+	env         bool
+	includeRoot string
+	pretty      bool
+	json5       bool
+	hjson       bool
+	python      bool
+}
+
+func (c *command) usage(msg string) int {
+	fmt.Fprintf(c.stderr, "extjson: %s\n%s", msg, usage)
+	return 2
+}
+
+func (c *command) options() []extjson.ExtOption {
+	var opts []extjson.ExtOption
+	if c.env {
+		opts = append(opts, extjson.EnableEnv())
+	}
+	if c.includeRoot != "" {
+		opts = append(opts, extjson.IncludeRoot(c.includeRoot))
+	}
+	if c.json5 {
+		opts = append(opts, extjson.EnableJSON5())
+	}
+	if c.hjson {
+		opts = append(opts, extjson.EnableHjson())
+	}
+	if c.python {
+		opts = append(opts, extjson.EnablePython())
+	}
+	return opts
+}
+
+// load reads and cleans the named file, or the standard input if
+// name is empty or "-".
+func (c *command) load(name string) ([]byte, error) {
+	var raw json.RawMessage
+	if name == "" || name == "-" {
+		data, err := io.ReadAll(c.stdin)
+		if err != nil {
+			return nil, err
+		}
+		err = extjson.Unmarshal(data, &raw, c.options()...)
+		return raw, err
+	}
+	err := extjson.Load(name, &raw, c.options()...)
+	return raw, err
+}
+
+func (c *command) clean(name string) error {
+	out, err := c.load(name)
+	if err != nil {
+		return err
+	}
+	return c.print(out)
+}
+
+func (c *command) check(names []string) int {
+	if len(names) == 0 {
+		names = []string{"-"}
+	}
+	status := 0
+	for _, name := range names {
+		if _, err := c.load(name); err != nil {
+			if name == "-" {
+				name = "<stdin>"
+			}
+			fmt.Fprintf(c.stderr, "%s: %v\n", name, err)
+			status = 1
+		}
+	}
+	return status
+}
+
+func (c *command) get(name, path string) error {
+	out, err := c.load(name)
+	if err != nil {
+		return err
+	}
+	result := gjson.GetBytes(out, path)
+	if !result.Exists() {
+		return errors.New("path not found: " + path)
+	}
+	return c.print([]byte(result.Raw))
+}
+
+func (c *command) print(out []byte) error {
+	var buf bytes.Buffer
+	var err error
+	if c.pretty {
+		err = json.Indent(&buf, out, "", "  ")
+	} else {
+		err = json.Compact(&buf, out)
+	}
+	if err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = c.stdout.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	good := filepath.Join(dir, "good.json")
+	bad := filepath.Join(dir, "bad.json")
+	err := os.WriteFile(good, []byte(`{
+		// comment
+		name: 'extjson',
+		list: [1, 2, 3,],
+		home: @env("EXTJSON_TEST_HOME"),
+	}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = os.WriteFile(bad, []byte("{\n  a: 1,\n  b: }"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EXTJSON_TEST_HOME", "/home/test")
+
+	testCases := []struct {
+		args   []string
+		stdin  string
+		status int
+		stdout string
+		stderr string
+	}{
+		{[]string{"clean", "-env", good}, "", 0, `{"name":"extjson","list":[1,2,3],"home":"/home/test"}` + "\n", ""},
+		{[]string{"clean", good}, "", 1, "", "env feature is not enabled"},
+		{[]string{"clean", "-pretty"}, "[1, 2,]", 0, "[\n  1,\n  2\n]\n", ""},
+		{[]string{"clean", "-json5", "-"}, "{a: 0x10}", 0, `{"a":16}` + "\n", ""},
+		{[]string{"check", "-env", good}, "", 0, "", ""},
+		{[]string{"check", "-env", good, bad}, "", 1, "", "bad.json: "},
+		{[]string{"check"}, "{a: 0x10}", 1, "", "<stdin>: JSON5 syntax is not allowed at 1:5"},
+		{[]string{"get", "-env", good, "list.1"}, "", 0, "2\n", ""},
+		{[]string{"get", "-env", good, "name"}, "", 0, `"extjson"` + "\n", ""},
+		{[]string{"get", "-env", good, "missing"}, "", 1, "", "path not found: missing"},
+		{[]string{"get", good, "list.1", "-env"}, "", 0, "2\n", ""},
+		{[]string{"clean", "-", "-json5"}, "{a: 0x10}", 0, `{"a":16}` + "\n", ""},
+		{[]string{"check", "-env", "--", good, "-env"}, "", 1, "", "-env: "},
+		{[]string{"get", good}, "", 2, "", "get requires a file and a path"},
+		{[]string{"unknown"}, "", 2, "", `unknown command "unknown"`},
+		{nil, "", 2, "", "Usage:"},
+	}
+	for _, tc := range testCases {
+		var stdout, stderr bytes.Buffer
+		status := run(tc.args, strings.NewReader(tc.stdin), &stdout, &stderr)
+		if status != tc.status {
+			t.Errorf("%v: status = %d, want %d, stderr = %s", tc.args, status, tc.status, stderr.String())
+		}
+		if stdout.String() != tc.stdout {
+			t.Errorf("%v: stdout = %q, want %q", tc.args, stdout.String(), tc.stdout)
+		}
+		if !strings.Contains(stderr.String(), tc.stderr) {
+			t.Errorf("%v: stderr = %q, want containing %q", tc.args, stderr.String(), tc.stderr)
+		}
+	}
+}
@@ -0,0 +1,1001 @@
+// Copyright 2010 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE-GO file.
+//
+// The decoding of values (indirect, literalStore and the xxxInterface
+// routines) and the struct field rules (typeDecodeFields, isValidTag,
+// foldName) in this file are adapted from encoding/json/decode.go,
+// encoding/json/encode.go and encoding/json/fold.go of the Go standard
+// library.
+
+package extjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// decodeState decodes the syntax tree of a document into Go values,
+// it follows the rules of encoding/json, except that the offsets of
+// errors are offsets in the source data.
+type decodeState struct {
+	useNumber             bool
+	disallowUnknownFields bool
+	caseSensitive         bool
+	applyDefaults         bool
+	validateTags          bool
+
+	// data is the data which the syntax tree is parsed from, scalars
+	// are decoded from it, sources maps its offsets to the source files.
+	data    []byte
+	sources *parser.SourceMap
+
+	savedError  error
+	errorStruct reflect.Type
+	fieldStack  []string
+}
+
+// hasOptions tells whether any option is used which json.Unmarshal
+// does not support.
+func (d *decodeState) hasOptions() bool {
+	return d.useNumber || d.disallowUnknownFields || d.caseSensitive || d.applyDefaults || d.validateTags
+}
+
+// decode parses data and decodes it into v from the syntax tree, which
+// is used for the options json.Unmarshal does not support. Documents
+// with directives are evaluated first, and the tree of the output is
+// mapped to the source files.
+func (d *decodeState) decode(data []byte, v interface{}, opts parser.Options) error {
+	tree, data, sources, err := parseTree(data, opts)
+	if err != nil {
+		return err
+	}
+	d.data, d.sources = data, sources
+	return d.unmarshal(tree.Root, v)
+}
+
+// parseTree parses data and returns the syntax tree of the document,
+// the data which the tree is parsed from, which is the output of the
+// directives if there are any, and the map of its offsets to the
+// source files.
+func parseTree(data []byte, opts parser.Options) (*parser.Tree, []byte, *parser.SourceMap, error) {
+	if bytes.IndexByte(data, '@') < 0 {
+		const directives = parser.FeatureEnv | parser.FeatureInclude | parser.FeatureRef | parser.FeatureFunc
+		tree, err := parser.ParseTree(data, opts)
+		if err == nil && tree.Features&directives == 0 {
+			return tree, data, parser.NewSourceMap(opts.FilePath, data), nil
+		}
+	}
+	out, sources, err := parser.ParseWithSourceMap(data, opts)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	tree, err := parser.ParseTree(out, parser.Options{})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return tree, out, sources, nil
+}
+
+func (d *decodeState) unmarshal(root *parser.Node, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	if err := d.value(root, rv); err != nil {
+		return d.addErrorContext(err)
+	}
+	if d.savedError != nil {
+		return d.savedError
+	}
+	if d.applyDefaults || d.validateTags {
+		return d.checkFields(root, rv)
+	}
+	return nil
+}
+
+// saveError saves the first err it is called with, for reporting at
+// the end of the unmarshal.
+func (d *decodeState) saveError(err error) {
+	if d.savedError == nil {
+		d.savedError = d.addErrorContext(err)
+	}
+}
+
+func (d *decodeState) addErrorContext(err error) error {
+	if d.errorStruct == nil && len(d.fieldStack) == 0 {
+		return err
+	}
+	if err, ok := err.(*json.UnmarshalTypeError); ok {
+		if d.errorStruct != nil {
+			err.Struct = d.errorStruct.Name()
+		}
+		fieldStack := d.fieldStack
+		if err.Field != "" {
+			fieldStack = append(fieldStack[:len(fieldStack):len(fieldStack)], err.Field)
+		}
+		err.Field = strings.Join(fieldStack, ".")
+	}
+	return err
+}
+
+func (d *decodeState) typeError(value string, t reflect.Type, n *parser.Node) error {
+	return &json.UnmarshalTypeError{Value: value, Type: t, Offset: int64(n.Begin)}
+}
+
+// raw returns the strict JSON of n, literals are mostly slices of the
+// data, objects and arrays are generated for json.Unmarshaler.
+func (d *decodeState) raw(n *parser.Node) []byte {
+	switch n.Kind {
+	case parser.NodeObject, parser.NodeArray:
+		return parser.AppendJSON(nil, d.data, n)
+	}
+	return parser.Literal(d.data, n)
+}
+
+func (d *decodeState) value(n *parser.Node, v reflect.Value) error {
+	if !v.IsValid() {
+		return nil
+	}
+	switch n.Kind {
+	case parser.NodeObject:
+		return d.object(n, v)
+	case parser.NodeArray:
+		return d.array(n, v)
+	}
+	return d.literalStore(n, d.raw(n), v, false)
+}
+
+// indirect walks down v allocating pointers as needed, until it gets
+// to a non-pointer. If it encounters an Unmarshaler, indirect stops and
+// returns that. If decodingNull is true, indirect stops at the first
+// settable pointer so it can be set to nil.
+func indirect(v reflect.Value, decodingNull bool) (json.Unmarshaler, encoding.TextUnmarshaler, reflect.Value) {
+	v0 := v
+	haveAddr := false
+
+	// If v is a named type and is addressable, start with its address,
+	// so that if the type has pointer methods, we find them.
+	if v.Kind() != reflect.Ptr && v.Type().Name() != "" && v.CanAddr() {
+		haveAddr = true
+		v = v.Addr()
+	}
+	for {
+		// Load value from interface, but only if the result will be
+		// usefully addressable.
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Ptr && !e.IsNil() && (!decodingNull || e.Elem().Kind() == reflect.Ptr) {
+				haveAddr = false
+				v = e
+				continue
+			}
+		}
+		if v.Kind() != reflect.Ptr {
+			break
+		}
+		if decodingNull && v.CanSet() {
+			break
+		}
+
+		// Prevent infinite loop if v is an interface pointing to its
+		// own address.
+		if v.Elem().Kind() == reflect.Interface && v.Elem().Elem() == v {
+			v = v.Elem()
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().NumMethod() > 0 && v.CanInterface() {
+			if u, ok := v.Interface().(json.Unmarshaler); ok {
+				return u, nil, reflect.Value{}
+			}
+			if !decodingNull {
+				if u, ok := v.Interface().(encoding.TextUnmarshaler); ok {
+					return nil, u, reflect.Value{}
+				}
+			}
+		}
+		if haveAddr {
+			v = v0 // restore original value after round-trip Value.Addr().Elem()
+			haveAddr = false
+		} else {
+			v = v.Elem()
+		}
+	}
+	return nil, nil, v
+}
+
+func (d *decodeState) array(n *parser.Node, v reflect.Value) error {
+	u, ut, pv := indirect(v, false)
+	if u != nil {
+		return u.UnmarshalJSON(d.raw(n))
+	}
+	if ut != nil {
+		d.saveError(d.typeError("array", v.Type(), n))
+		return nil
+	}
+	v = pv
+
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(d.arrayInterface(n)))
+			return nil
+		}
+		d.saveError(d.typeError("array", v.Type(), n))
+		return nil
+	case reflect.Array, reflect.Slice:
+	default:
+		d.saveError(d.typeError("array", v.Type(), n))
+		return nil
+	}
+
+	i := 0
+	for _, elem := range n.Children {
+		if v.Kind() == reflect.Slice {
+			if i >= v.Cap() {
+				grown := reflect.MakeSlice(v.Type(), v.Len(), growCap(v.Cap(), len(n.Children)))
+				reflect.Copy(grown, v)
+				v.Set(grown)
+			}
+			if i >= v.Len() {
+				v.SetLen(i + 1)
+			}
+		}
+		if i < v.Len() {
+			if err := d.value(elem, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		i++
+	}
+	if i < v.Len() {
+		if v.Kind() == reflect.Array {
+			zero := reflect.Zero(v.Type().Elem())
+			for ; i < v.Len(); i++ {
+				v.Index(i).Set(zero) // zero remainder of array
+			}
+		} else {
+			v.SetLen(i) // truncate the slice
+		}
+	}
+	if i == 0 && v.Kind() == reflect.Slice {
+		v.Set(reflect.MakeSlice(v.Type(), 0, 0))
+	}
+	return nil
+}
+
+// growCap returns the capacity to grow a slice to, the final length
+// is known from the syntax tree.
+func growCap(cap, want int) int {
+	if c := cap + cap/2; c > want {
+		return c
+	}
+	return want
+}
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+func (d *decodeState) object(n *parser.Node, v reflect.Value) error {
+	u, ut, pv := indirect(v, false)
+	if u != nil {
+		return u.UnmarshalJSON(d.raw(n))
+	}
+	if ut != nil {
+		d.saveError(d.typeError("object", v.Type(), n))
+		return nil
+	}
+	v = pv
+	t := v.Type()
+
+	if v.Kind() == reflect.Interface && v.NumMethod() == 0 {
+		v.Set(reflect.ValueOf(d.objectInterface(n)))
+		return nil
+	}
+
+	// Check type of target: struct or map[T1]T2 where T1 is string,
+	// an integer type, or an encoding.TextUnmarshaler.
+	var fields *decodeFields
+	switch v.Kind() {
+	case reflect.Map:
+		switch t.Key().Kind() {
+		case reflect.String,
+			reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+			reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		default:
+			if !reflect.PtrTo(t.Key()).Implements(textUnmarshalerType) {
+				d.saveError(d.typeError("object", t, n))
+				return nil
+			}
+		}
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+	case reflect.Struct:
+		fields = cachedDecodeFields(t)
+	default:
+		d.saveError(d.typeError("object", t, n))
+		return nil
+	}
+
+	var mapElem reflect.Value
+	origStruct, origDepth := d.errorStruct, len(d.fieldStack)
+	for _, member := range n.Children {
+		key := member.Key
+
+		var subv reflect.Value
+		destring := false // whether the value is wrapped in a string to be decoded first
+		if v.Kind() == reflect.Map {
+			if !mapElem.IsValid() {
+				mapElem = reflect.New(t.Elem()).Elem()
+			} else {
+				mapElem.Set(reflect.Zero(t.Elem()))
+			}
+			subv = mapElem
+		} else if f := fields.lookup(key, d.caseSensitive); f != nil {
+			subv = v
+			destring = f.quoted
+			for i, ind := range f.index {
+				if subv.Kind() == reflect.Ptr {
+					if subv.IsNil() {
+						// If a struct embeds a pointer to an unexported type,
+						// it is not possible to set a newly allocated value
+						// since the field is unexported.
+						if !subv.CanSet() {
+							d.saveError(fmt.Errorf("json: cannot set embedded pointer to unexported struct: %v", subv.Type().Elem()))
+							subv = reflect.Value{}
+							destring = false
+							break
+						}
+						subv.Set(reflect.New(subv.Type().Elem()))
+					}
+					subv = subv.Elem()
+				}
+				if i < len(f.index)-1 {
+					d.fieldStack = append(d.fieldStack, subv.Type().Field(ind).Name)
+				}
+				subv = subv.Field(ind)
+			}
+			d.errorStruct = t
+			d.fieldStack = append(d.fieldStack, f.name)
+		} else if d.disallowUnknownFields {
+			d.saveError(d.unknownField(member, t))
+		}
+
+		if destring {
+			switch member.Kind {
+			case parser.NodeNull:
+				if err := d.literalStore(member, d.raw(member), subv, false); err != nil {
+					return err
+				}
+			case parser.NodeString:
+				if err := d.literalStore(member, []byte(member.Value), subv, true); err != nil {
+					return err
+				}
+			default:
+				d.saveError(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal unquoted value into %v", subv.Type()))
+			}
+		} else if err := d.value(member, subv); err != nil {
+			return err
+		}
+
+		// Write value back to map, if using struct, subv points into
+		// struct already.
+		if v.Kind() == reflect.Map {
+			kv, err := d.mapKey(member, t.Key())
+			if err != nil {
+				return err
+			}
+			if kv.IsValid() {
+				v.SetMapIndex(kv, subv)
+			}
+		}
+		d.errorStruct, d.fieldStack = origStruct, d.fieldStack[:origDepth]
+	}
+	return nil
+}
+
+func (d *decodeState) mapKey(member *parser.Node, kt reflect.Type) (reflect.Value, error) {
+	key := member.Key
+	if reflect.PtrTo(kt).Implements(textUnmarshalerType) {
+		kv := reflect.New(kt)
+		item, _ := json.Marshal(key)
+		if err := d.literalStore(member, item, kv, true); err != nil {
+			return reflect.Value{}, err
+		}
+		return kv.Elem(), nil
+	}
+	kv := reflect.New(kt).Elem()
+	switch kt.Kind() {
+	case reflect.String:
+		kv.SetString(key)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || kt.OverflowInt(x) {
+			d.saveError(&json.UnmarshalTypeError{Value: "number " + key, Type: kt, Offset: int64(member.KeyBegin)})
+			return reflect.Value{}, nil
+		}
+		kv.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || kt.OverflowUint(x) {
+			d.saveError(&json.UnmarshalTypeError{Value: "number " + key, Type: kt, Offset: int64(member.KeyBegin)})
+			return reflect.Value{}, nil
+		}
+		kv.SetUint(x)
+	}
+	return kv, nil
+}
+
+// UnknownFieldError is returned when an object key does not match any
+// field of the struct, if the option DisallowUnknownFields is used.
+type UnknownFieldError struct {
+	Key    string
+	Type   reflect.Type // type of the struct
+	Source Source       // location of the key
+}
+
+func (e *UnknownFieldError) Error() string {
+	return fmt.Sprintf("json: unknown field %q of %v at %v", e.Key, e.Type, e.Source)
+}
+
+func (d *decodeState) unknownField(member *parser.Node, t reflect.Type) error {
+	src, _ := d.sources.Lookup(member.KeyBegin)
+	return &UnknownFieldError{Key: member.Key, Type: t, Source: src}
+}
+
+var numberType = reflect.TypeOf(json.Number(""))
+
+// literalStore decodes the literal item of n into v, item is strict
+// JSON, or the content of a string for the ",string" struct tag option,
+// which is indicated by fromQuoted.
+func (d *decodeState) literalStore(n *parser.Node, item []byte, v reflect.Value, fromQuoted bool) error {
+	if len(item) == 0 {
+		d.saveError(fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %q into %v", item, v.Type()))
+		return nil
+	}
+	invalidQuoted := func() error {
+		return fmt.Errorf("json: invalid use of ,string struct tag, trying to unmarshal %q into %v", item, v.Type())
+	}
+	isNull := item[0] == 'n' // null
+	u, ut, pv := indirect(v, isNull)
+	if u != nil {
+		return u.UnmarshalJSON(item)
+	}
+	if ut != nil {
+		if item[0] != '"' {
+			if fromQuoted {
+				d.saveError(invalidQuoted())
+				return nil
+			}
+			val := "number"
+			switch item[0] {
+			case 'n':
+				val = "null"
+			case 't', 'f':
+				val = "bool"
+			}
+			d.saveError(d.typeError(val, v.Type(), n))
+			return nil
+		}
+		s, ok := d.unquote(n, item, fromQuoted)
+		if !ok {
+			return invalidQuoted()
+		}
+		return ut.UnmarshalText([]byte(s))
+	}
+
+	v = pv
+	switch c := item[0]; c {
+	case 'n': // null
+		if fromQuoted && string(item) != "null" {
+			d.saveError(invalidQuoted())
+			break
+		}
+		switch v.Kind() {
+		case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+			v.Set(reflect.Zero(v.Type()))
+			// otherwise, ignore null for primitives/string
+		}
+	case 't', 'f': // true, false
+		value := item[0] == 't'
+		if fromQuoted && string(item) != "true" && string(item) != "false" {
+			d.saveError(invalidQuoted())
+			break
+		}
+		switch v.Kind() {
+		default:
+			if fromQuoted {
+				d.saveError(invalidQuoted())
+			} else {
+				d.saveError(d.typeError("bool", v.Type(), n))
+			}
+		case reflect.Bool:
+			v.SetBool(value)
+		case reflect.Interface:
+			if v.NumMethod() == 0 {
+				v.Set(reflect.ValueOf(value))
+			} else {
+				d.saveError(d.typeError("bool", v.Type(), n))
+			}
+		}
+	case '"': // string
+		s, ok := d.unquote(n, item, fromQuoted)
+		if !ok {
+			return invalidQuoted()
+		}
+		switch v.Kind() {
+		default:
+			d.saveError(d.typeError("string", v.Type(), n))
+		case reflect.Slice:
+			if v.Type().Elem().Kind() != reflect.Uint8 {
+				d.saveError(d.typeError("string", v.Type(), n))
+				break
+			}
+			b := make([]byte, base64.StdEncoding.DecodedLen(len(s)))
+			n, err := base64.StdEncoding.Decode(b, []byte(s))
+			if err != nil {
+				d.saveError(err)
+				break
+			}
+			v.SetBytes(b[:n])
+		case reflect.String:
+			if v.Type() == numberType && !isValidNumber(s) {
+				return fmt.Errorf("json: invalid number literal, trying to unmarshal %q into Number", item)
+			}
+			v.SetString(s)
+		case reflect.Interface:
+			if v.NumMethod() == 0 {
+				v.Set(reflect.ValueOf(s))
+			} else {
+				d.saveError(d.typeError("string", v.Type(), n))
+			}
+		}
+	default: // number
+		if c != '-' && (c < '0' || c > '9') || fromQuoted && !isValidNumber(string(item)) {
+			return invalidQuoted()
+		}
+		switch v.Kind() {
+		default:
+			if v.Kind() == reflect.String && v.Type() == numberType {
+				v.SetString(string(item))
+				break
+			}
+			if fromQuoted {
+				return invalidQuoted()
+			}
+			d.saveError(d.typeError("number", v.Type(), n))
+		case reflect.Interface:
+			x, err := d.convertNumber(n, string(item))
+			if err != nil {
+				d.saveError(err)
+				break
+			}
+			if v.NumMethod() != 0 {
+				d.saveError(d.typeError("number", v.Type(), n))
+				break
+			}
+			v.Set(reflect.ValueOf(x))
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			x, err := strconv.ParseInt(string(item), 10, 64)
+			if err != nil || v.OverflowInt(x) {
+				d.saveError(d.typeError("number "+string(item), v.Type(), n))
+				break
+			}
+			v.SetInt(x)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+			x, err := strconv.ParseUint(string(item), 10, 64)
+			if err != nil || v.OverflowUint(x) {
+				d.saveError(d.typeError("number "+string(item), v.Type(), n))
+				break
+			}
+			v.SetUint(x)
+		case reflect.Float32, reflect.Float64:
+			x, err := strconv.ParseFloat(string(item), v.Type().Bits())
+			if err != nil || v.OverflowFloat(x) {
+				d.saveError(d.typeError("number "+string(item), v.Type(), n))
+				break
+			}
+			v.SetFloat(x)
+		}
+	}
+	return nil
+}
+
+// unquote returns the value of the quoted string item, which is the
+// decoded value of n unless item is from the ",string" option or a
+// number converted to string.
+func (d *decodeState) unquote(n *parser.Node, item []byte, fromQuoted bool) (string, bool) {
+	if !fromQuoted && n.Kind == parser.NodeString {
+		return n.Value, true
+	}
+	return unquoteItem(item)
+}
+
+func unquoteItem(item []byte) (s string, ok bool) {
+	return s, json.Unmarshal(item, &s) == nil
+}
+
+func (d *decodeState) convertNumber(n *parser.Node, s string) (interface{}, error) {
+	if d.useNumber {
+		return json.Number(s), nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, d.typeError("number "+s, reflect.TypeOf(0.0), n)
+	}
+	return f, nil
+}
+
+// isValidNumber reports whether s is a valid JSON number literal.
+func isValidNumber(s string) bool {
+	if s == "" {
+		return false
+	}
+	if s[0] == '-' {
+		if s = s[1:]; s == "" {
+			return false
+		}
+	}
+	switch {
+	case s[0] == '0':
+		s = s[1:]
+	case '1' <= s[0] && s[0] <= '9':
+		s = skipDigits(s[1:])
+	default:
+		return false
+	}
+	// . followed by 1 or more digits.
+	if len(s) >= 2 && s[0] == '.' && isDigit(s[1]) {
+		s = skipDigits(s[2:])
+	}
+	// e or E followed by an optional - or + and 1 or more digits.
+	if len(s) >= 2 && (s[0] == 'e' || s[0] == 'E') {
+		s = s[1:]
+		if s[0] == '+' || s[0] == '-' {
+			if s = s[1:]; s == "" {
+				return false
+			}
+		}
+		s = skipDigits(s)
+	}
+	return s == ""
+}
+
+func isDigit(c byte) bool { return '0' <= c && c <= '9' }
+
+func skipDigits(s string) string {
+	for len(s) > 0 && isDigit(s[0]) {
+		s = s[1:]
+	}
+	return s
+}
+
+// The xxxInterface routines build up a value to be stored in an empty
+// interface, they avoid the weight of reflection in this common case.
+
+func (d *decodeState) valueInterface(n *parser.Node) interface{} {
+	switch n.Kind {
+	case parser.NodeObject:
+		return d.objectInterface(n)
+	case parser.NodeArray:
+		return d.arrayInterface(n)
+	}
+	return d.literalInterface(n)
+}
+
+func (d *decodeState) arrayInterface(n *parser.Node) []interface{} {
+	v := make([]interface{}, 0, len(n.Children))
+	for _, elem := range n.Children {
+		v = append(v, d.valueInterface(elem))
+	}
+	return v
+}
+
+func (d *decodeState) objectInterface(n *parser.Node) map[string]interface{} {
+	m := make(map[string]interface{}, len(n.Children))
+	for _, member := range n.Children {
+		m[member.Key] = d.valueInterface(member)
+	}
+	return m
+}
+
+func (d *decodeState) literalInterface(n *parser.Node) interface{} {
+	item := d.raw(n)
+	switch c := item[0]; c {
+	case 'n': // null
+		return nil
+	case 't', 'f': // true, false
+		return c == 't'
+	case '"': // string
+		s, _ := d.unquote(n, item, false)
+		return s
+	default: // number
+		x, err := d.convertNumber(n, string(item))
+		if err != nil {
+			d.saveError(err)
+		}
+		return x
+	}
+}
+
+// A decodeField represents a single field found in a struct.
+type decodeField struct {
+	name      string
+	tag       bool
+	index     []int
+	typ       reflect.Type
+	quoted    bool
+	omitEmpty bool
+	extName   string // from the "extjson" tag, used by Encoder
+	comment   string // from the "extjson" tag, used by Encoder
+}
+
+type decodeFields struct {
+	list         []decodeField
+	byExactName  map[string]*decodeField
+	byFoldedName map[string]*decodeField
+}
+
+// lookup returns the field matching key, preferring an exact match
+// to a case-insensitive match.
+func (s *decodeFields) lookup(key string, caseSensitive bool) *decodeField {
+	if f := s.byExactName[key]; f != nil || caseSensitive {
+		return f
+	}
+	return s.byFoldedName[foldName(key)]
+}
+
+var decodeFieldCache sync.Map // map[reflect.Type]*decodeFields
+
+func cachedDecodeFields(t reflect.Type) *decodeFields {
+	if f, ok := decodeFieldCache.Load(t); ok {
+		return f.(*decodeFields)
+	}
+	f, _ := decodeFieldCache.LoadOrStore(t, typeDecodeFields(t))
+	return f.(*decodeFields)
+}
+
+// typeDecodeFields returns the fields which JSON should recognize for the
+// given type, following the rules of encoding/json for embedded structs.
+func typeDecodeFields(t reflect.Type) *decodeFields {
+	// Anonymous fields to explore at the current level and the next.
+	current := []decodeField{}
+	next := []decodeField{{typ: t}}
+
+	// Count of queued names for current level and the next.
+	var count, nextCount map[reflect.Type]int
+
+	// Types already visited at an earlier level.
+	visited := map[reflect.Type]bool{}
+
+	var fields []decodeField
+	for len(next) > 0 {
+		current, next = next, current[:0]
+		count, nextCount = nextCount, map[reflect.Type]int{}
+
+		for _, f := range current {
+			if visited[f.typ] {
+				continue
+			}
+			visited[f.typ] = true
+
+			for i := 0; i < f.typ.NumField(); i++ {
+				sf := f.typ.Field(i)
+				exported := sf.PkgPath == ""
+				if sf.Anonymous {
+					t := sf.Type
+					if t.Kind() == reflect.Ptr {
+						t = t.Elem()
+					}
+					if !exported && t.Kind() != reflect.Struct {
+						// Ignore embedded fields of unexported non-struct types.
+						continue
+					}
+					// Do not ignore embedded fields of unexported struct
+					// types since they may have exported fields.
+				} else if !exported {
+					continue
+				}
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				if !isValidTag(name) {
+					name = ""
+				}
+				extName, comment := parseExtTag(sf.Tag.Get("extjson"))
+				if !isValidTag(extName) {
+					extName = ""
+				}
+				index := make([]int, len(f.index)+1)
+				copy(index, f.index)
+				index[len(f.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+
+				// Only strings, floats, integers, and booleans can be quoted.
+				quoted := hasTagOption(opts, "string") && isQuotable(ft.Kind())
+
+				// Record found field and index sequence.
+				if name != "" || !sf.Anonymous || ft.Kind() != reflect.Struct {
+					tagged := name != ""
+					if name == "" {
+						name = sf.Name
+					}
+					fields = append(fields, decodeField{
+						name:      name,
+						tag:       tagged,
+						index:     index,
+						typ:       ft,
+						quoted:    quoted,
+						omitEmpty: hasTagOption(opts, "omitempty"),
+						extName:   extName,
+						comment:   comment,
+					})
+					if count[f.typ] > 1 {
+						// If there were multiple instances, add a second,
+						// so that the annihilation code will see a duplicate.
+						fields = append(fields, fields[len(fields)-1])
+					}
+					continue
+				}
+
+				// Record new anonymous struct to explore in next round.
+				nextCount[ft]++
+				if nextCount[ft] == 1 {
+					next = append(next, decodeField{name: ft.Name(), index: index, typ: ft})
+				}
+			}
+		}
+	}
+
+	// Sort fields by name, breaking ties with depth, then breaking ties
+	// with "name came from json tag", then breaking ties with index sequence.
+	sort.Slice(fields, func(i, j int) bool {
+		a, b := fields[i], fields[j]
+		if a.name != b.name {
+			return a.name < b.name
+		}
+		if len(a.index) != len(b.index) {
+			return len(a.index) < len(b.index)
+		}
+		if a.tag != b.tag {
+			return a.tag
+		}
+		return lessIndex(a.index, b.index)
+	})
+
+	// Delete all fields that are hidden by the Go rules for embedded
+	// fields, except that fields with JSON tags are promoted.
+	out := fields[:0]
+	for advance, i := 0, 0; i < len(fields); i += advance {
+		fi := fields[i]
+		for advance = 1; i+advance < len(fields); advance++ {
+			if fields[i+advance].name != fi.name {
+				break
+			}
+		}
+		if advance == 1 {
+			out = append(out, fi)
+			continue
+		}
+		// The first decodeField is the dominant one, unless there are two
+		// fields at top level, either both tagged or neither tagged.
+		if fj := fields[i+1]; len(fi.index) != len(fj.index) || fi.tag != fj.tag {
+			out = append(out, fi)
+		}
+	}
+	fields = out
+	sort.Slice(fields, func(i, j int) bool {
+		return lessIndex(fields[i].index, fields[j].index)
+	})
+
+	s := &decodeFields{
+		list:         fields,
+		byExactName:  make(map[string]*decodeField, len(fields)),
+		byFoldedName: make(map[string]*decodeField, len(fields)),
+	}
+	for i := range fields {
+		f := &fields[i]
+		s.byExactName[f.name] = f
+		// For historical reasons, first folded match takes precedence.
+		if folded := foldName(f.name); s.byFoldedName[folded] == nil {
+			s.byFoldedName[folded] = f
+		}
+	}
+	return s
+}
+
+func lessIndex(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func parseTag(tag string) (name, opts string) {
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		return tag[:i], tag[i+1:]
+	}
+	return tag, ""
+}
+
+func hasTagOption(opts, name string) bool {
+	for opts != "" {
+		var opt string
+		if i := strings.IndexByte(opts, ','); i >= 0 {
+			opt, opts = opts[:i], opts[i+1:]
+		} else {
+			opt, opts = opts, ""
+		}
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
+
+func isValidTag(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, c := range s {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+			// Backslash and quote chars are reserved, but otherwise any
+			// punctuation chars are allowed in a tag name.
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			return false
+		}
+	}
+	return true
+}
+
+// foldName returns a folded string such that foldName(x) == foldName(y)
+// is identical to strings.EqualFold(x, y).
+func foldName(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			if 'a' <= r && r <= 'z' {
+				r -= 'a' - 'A'
+			}
+			b.WriteByte(byte(r))
+			continue
+		}
+		b.WriteRune(foldRune(r))
+	}
+	return b.String()
+}
+
+// foldRune returns the smallest rune for all runes in the same fold set.
+func foldRune(r rune) rune {
+	for {
+		r2 := unicode.SimpleFold(r)
+		if r2 <= r {
+			return r2
+		}
+		r = r2
+	}
+}
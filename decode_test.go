@@ -0,0 +1,401 @@
+package extjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+type decodeTextKey struct{ s string }
+
+func (k *decodeTextKey) UnmarshalText(b []byte) error {
+	if string(b) == "bad" {
+		return errors.New("bad text")
+	}
+	k.s = strings.ToUpper(string(b))
+	return nil
+}
+
+type decodeUnmarshaler struct{ raw string }
+
+func (u *decodeUnmarshaler) UnmarshalJSON(b []byte) error {
+	u.raw = string(b)
+	return nil
+}
+
+type DecodeEmbedded struct {
+	E1 int
+	E2 string `json:"e2"`
+	X  int    // hidden by decodeStruct.X
+}
+
+type decodeInner struct {
+	Inner  string
+	Hidden int `json:"-"`
+}
+
+type decodeStruct struct {
+	DecodeEmbedded
+	*decodeInner
+
+	X       int
+	Name    string `json:"name"`
+	Num     int64  `json:"num,string"`
+	Flag    bool   `json:",string"`
+	Str     string `json:"str,string"`
+	F32     float32
+	U8      uint8
+	Ptr     *int
+	PPtr    **string
+	Slice   []int
+	Array   [3]int
+	Bytes   []byte
+	Any     interface{}
+	Map     map[string]int
+	IntMap  map[int]string
+	UintMap map[uint8]bool
+	TextMap map[decodeTextKey]int
+	Text    decodeTextKey
+	TextPtr *decodeTextKey
+	Raw     json.RawMessage
+	Custom  decodeUnmarshaler
+	Number  json.Number
+	Nested  *decodeStruct
+	Structs []decodeStruct
+
+	unexported int
+}
+
+func decodeTargets() map[string]func() interface{} {
+	return map[string]func() interface{}{
+		"interface": func() interface{} { return new(interface{}) },
+		"struct":    func() interface{} { return new(decodeStruct) },
+		"map":       func() interface{} { return new(map[string]interface{}) },
+		"mapInt":    func() interface{} { return new(map[string]int) },
+		"slice":     func() interface{} { return new([]interface{}) },
+		"array":     func() interface{} { return new([2]interface{}) },
+		"string":    func() interface{} { return new(string) },
+		"float":     func() interface{} { return new(float64) },
+		"int":       func() interface{} { return new(int) },
+		"bool":      func() interface{} { return new(bool) },
+		"ptr":       func() interface{} { return new(*decodeStruct) },
+		"raw":       func() interface{} { return new(json.RawMessage) },
+		"number":    func() interface{} { return new(json.Number) },
+		"text":      func() interface{} { return new(decodeTextKey) },
+		"prefilled": func() interface{} {
+			x := 1
+			return &decodeStruct{
+				Name:   "old",
+				Ptr:    &x,
+				Slice:  make([]int, 5, 10),
+				Array:  [3]int{1, 2, 3},
+				Map:    map[string]int{"old": 1},
+				Nested: &decodeStruct{Name: "nested"},
+			}
+		},
+		"prefilledAny": func() interface{} {
+			var v interface{} = &decodeStruct{Name: "old"}
+			return &v
+		},
+	}
+}
+
+var decodeTestData = []string{
+	`null`,
+	`true`,
+	`1.5e3`,
+	`"abc"`,
+	`'single'`,
+	`[1, "a", null, true, [], {}, ]`,
+	`[1, 2, 3]`,
+	`{}`,
+	`{a: 1, b: 2,}`,
+	`{
+		// comment
+		X: 1, E1: 2, e2: "e", inner: "in", hidden: 3,
+		name: "n", NAME: "upper", num: "123", Flag: "true", str: "\"quoted\"",
+		F32: 1.5, U8: 255, Ptr: 42, PPtr: "pp",
+		Slice: [1, 2], Array: [7], Bytes: "aGVsbG8=",
+		Any: {x: [1, {y: null}]},
+		Map: {a: 1, b: 2}, IntMap: {"1": "a", "-2": "b"}, UintMap: {"7": true},
+		TextMap: {k: 1}, Text: "text", TextPtr: "ptr",
+		Raw: {a: [1, 2]}, Custom: ['x', ], Number: 12.5e1,
+		Nested: {name: "nested", Nested: null}, Structs: [{X: 1}, {name: "s"}],
+		unexported: 1, unknown: {a: 1},
+	}`,
+	`{Ptr: null, Slice: null, Map: null, Nested: null, Any: null, X: null, Name: null, Array: []}`,
+	`{num: null, Flag: null, num: 1}`,
+	`{str: "abc"}`,
+	`{num: "12x"}`,
+	`{Flag: "yes"}`,
+	`{X: "1"}`,
+	`{X: 1.5}`,
+	`{U8: 256}`,
+	`{U8: -1}`,
+	`{F32: 1e40}`,
+	`{Name: 1, X: 2}`,
+	`{Slice: {}, Map: []}`,
+	`{Slice: [1, "a", 3]}`,
+	`{Nested: {Nested: {X: "deep"}}}`,
+	`{Structs: [{}, {X: true}]}`,
+	`{IntMap: {a: "x", "1": "y"}}`,
+	`{UintMap: {"300": true}}`,
+	`{TextMap: {bad: 1}}`,
+	`{Text: 1}`,
+	`{Text: "bad"}`,
+	`{Bytes: "not base64"}`,
+	`{Bytes: [1, 2]}`,
+	`{Number: "12"}`,
+	`{Number: "abc"}`,
+	`{DecodeEmbedded: {E1: 1}}`,
+	`{Any: 1, Any: "dup"}`,
+	`"\u00e9\ud83d\ude00"`,
+	`{"\u0041ny": 1}`,
+}
+
+func TestDecodeConformance(t *testing.T) {
+	configs := []struct {
+		name  string
+		opts  []ExtOption
+		setup func(dec *json.Decoder)
+	}{
+		{"default", nil, func(dec *json.Decoder) {}},
+		{"UseNumber", []ExtOption{UseNumber()}, (*json.Decoder).UseNumber},
+		{"DisallowUnknownFields", []ExtOption{DisallowUnknownFields()}, (*json.Decoder).DisallowUnknownFields},
+	}
+	for _, config := range configs {
+		for _, data := range decodeTestData {
+			strict, err := parser.Parse([]byte(data), parser.Options{})
+			if err != nil {
+				t.Fatalf("failed parse %s: %v", data, err)
+			}
+			for name, target := range decodeTargets() {
+				got, want := target(), target()
+				gotErr := Unmarshal([]byte(data), got, config.opts...)
+				dec := json.NewDecoder(bytes.NewReader(strict))
+				config.setup(dec)
+				wantErr := dec.Decode(want)
+				if (gotErr == nil) != (wantErr == nil) {
+					t.Errorf("%s: %s into %s: got error %v, want %v", config.name, data, name, gotErr, wantErr)
+					continue
+				}
+				if gotErr == nil && !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: %s into %s:\ngot  = %s\nwant = %s", config.name, data, name, dumpValue(got), dumpValue(want))
+				}
+			}
+		}
+	}
+}
+
+func TestDecodeConformance_NonFinite(t *testing.T) {
+	data := `{Any: [Infinity, -Infinity, NaN], Name: Infinity}`
+	strict, err := parser.Parse([]byte(data), parser.Options{JSON5: true, NonFinite: parser.NonFiniteString})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want decodeStruct
+	gotErr := Unmarshal([]byte(data), &got, EnableJSON5(), ConvertNonFinite(NonFiniteString))
+	wantErr := json.Unmarshal(strict, &want)
+	if gotErr != nil || wantErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s, %v\nwant %s, %v", dumpValue(got), gotErr, dumpValue(want), wantErr)
+	}
+}
+
+func TestDecodeConformance_ConvertedLiterals(t *testing.T) {
+	// The decoder options decode from the syntax tree, whose literals
+	// are converted from the source data.
+	data := `{
+		X: 0x1F, F32: .5, U8: +7, Any: [0xff, 1., Infinity, True, None, 'it\'s', "\x41"],
+		Name: 'say "hi"', Bytes: 'aGk\x3d', Slice: [0x1, +2], Raw: {a: [0x10, 'b'], c: {}},
+		Custom: [NaN, u'\u00e9', b'\x00'], Number: -0x10,
+	}`
+	popts := parser.Options{JSON5: true, Python: true, NonFinite: parser.NonFiniteString}
+	strict, err := parser.Parse([]byte(data), popts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got, want decodeStruct
+	gotErr := Unmarshal([]byte(data), &got, EnableJSON5(), EnablePython(), ConvertNonFinite(NonFiniteString), UseNumber())
+	dec := json.NewDecoder(bytes.NewReader(strict))
+	dec.UseNumber()
+	wantErr := dec.Decode(&want)
+	if gotErr != nil || wantErr != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s, %v\nwant %s, %v", dumpValue(got), gotErr, dumpValue(want), wantErr)
+	}
+}
+
+func TestDecode_UseNumber(t *testing.T) {
+	testCases := []string{
+		`{a: 1.50, b: [-0, 1e+3], c: "1", d: 12345678901234567890}`,
+		`{a: 1.50, b: [-0, 1e+3], c: "1", d: @ref("e"), e: 12345678901234567890}`,
+	}
+	want := map[string]interface{}{
+		"a": json.Number("1.50"),
+		"b": []interface{}{json.Number("-0"), json.Number("1e+3")},
+		"c": "1",
+		"d": json.Number("12345678901234567890"),
+	}
+	for _, data := range testCases {
+		var got map[string]interface{}
+		if err := Unmarshal([]byte(data), &got, UseNumber()); err != nil {
+			t.Fatalf("failed unmarshal %s: %v", data, err)
+		}
+		delete(got, "e")
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %#v, want %#v", data, got, want)
+		}
+	}
+}
+
+func TestDecode_DisallowUnknownFields(t *testing.T) {
+	dir := t.TempDir()
+	incPath := filepath.Join(dir, "inc.json")
+	if err := os.WriteFile(incPath, []byte("{\n  port: 80,\n  hots: 'x',\n}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	type server struct {
+		Host string
+		Port int
+	}
+	type config struct {
+		Name    string   `json:"name"`
+		Servers []server `json:"servers"`
+		Default server   `json:"default"`
+	}
+	testCases := []struct {
+		data string
+		key  string
+		src  string
+	}{
+		{"{\n  name: 'x',\n  nmae: 'y',\n}", "nmae", "3:3"},
+		{"// comment\n{servers: [{host: 'a'}, {prot: 1}]}", "prot", "2:26"},
+		{"{\n  servers: [@incl(\"inc.json\")],\n}", "hots", incPath + ":3:3"},
+		{"{\n  default: {host: 'a', pot: 1},\n  servers: [@ref('default')],\n}", "pot", "2:24"},
+		{`{"name": "x", "Servers": [{"HOST": "a", "port": 1, "user": "u"}]}`, "user", "1:52"},
+	}
+	for _, tc := range testCases {
+		var v config
+		err := Unmarshal([]byte(tc.data), &v, DisallowUnknownFields(), IncludeRoot(dir))
+		fieldErr, ok := err.(*UnknownFieldError)
+		if !ok {
+			t.Errorf("%s: got error %v, want UnknownFieldError", tc.data, err)
+			continue
+		}
+		if fieldErr.Key != tc.key || fieldErr.Source.String() != tc.src {
+			t.Errorf("%s: got key %q at %v, want %q at %v", tc.data, fieldErr.Key, fieldErr.Source, tc.key, tc.src)
+		}
+	}
+
+	var v config
+	err := Unmarshal([]byte(`{name: 'x', servers: [{host: 'a'}]}`), &v, DisallowUnknownFields())
+	if err != nil || v.Name != "x" || len(v.Servers) != 1 || v.Servers[0].Host != "a" {
+		t.Errorf("got %+v, %v", v, err)
+	}
+}
+
+func TestDecode_CaseSensitive(t *testing.T) {
+	type config struct {
+		Name string `json:"name"`
+		Port int
+	}
+	var v config
+	err := Unmarshal([]byte(`{NAME: 'x', name: 'y', port: 1}`), &v, CaseSensitive())
+	if err != nil || v.Name != "y" || v.Port != 0 {
+		t.Errorf("got %+v, %v", v, err)
+	}
+	err = Unmarshal([]byte("{\n  Name: 'x',\n}"), &v, CaseSensitive(), DisallowUnknownFields())
+	if err == nil || err.Error() != `json: unknown field "Name" of extjson.config at 2:3` {
+		t.Errorf("expecting unknown field error, got %v", err)
+	}
+}
+
+func TestDecode_ErrorOffset(t *testing.T) {
+	data := "{\n  // comment\n  X: 'str',\n}"
+	var v decodeStruct
+	err := Unmarshal([]byte(data), &v)
+	typeErr, ok := err.(*json.UnmarshalTypeError)
+	if !ok {
+		t.Fatalf("got error %v, want UnmarshalTypeError", err)
+	}
+	if want := strings.Index(data, "'str'"); typeErr.Offset != int64(want) || typeErr.Field != "X" {
+		t.Errorf("got offset %d field %q, want %d, X", typeErr.Offset, typeErr.Field, want)
+	}
+}
+
+func TestDecode_InvalidTarget(t *testing.T) {
+	var v decodeStruct
+	for _, target := range []interface{}{nil, v, (*decodeStruct)(nil)} {
+		err := Unmarshal([]byte(`{a: 1}`), target)
+		if _, ok := err.(*json.InvalidUnmarshalError); !ok {
+			t.Errorf("%T: got error %v, want InvalidUnmarshalError", target, err)
+		}
+	}
+}
+
+func dumpValue(v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%#v", v)
+	}
+	return strconv.Quote(string(b))
+}
+
+type benchItem struct {
+	ID    int      `json:"id"`
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	OK    bool     `json:"ok"`
+	Score float64  `json:"score"`
+}
+
+var benchExtendedData = append([]byte("// comment\n"), benchStrictJSON...)
+
+func BenchmarkUnmarshal_ExtendedJSON_Struct(b *testing.B) {
+	b.SetBytes(int64(len(benchExtendedData)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v struct{ Items []benchItem }
+		if err := Unmarshal(benchExtendedData, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkClean_ExtendedJSON_Struct is the baseline of decoding the
+// strict JSON converted by Clean with json.Unmarshal.
+func BenchmarkClean_ExtendedJSON_Struct(b *testing.B) {
+	b.SetBytes(int64(len(benchExtendedData)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v struct{ Items []benchItem }
+		data, err := Clean(benchExtendedData)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if err = json.Unmarshal(data, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkUnmarshal_ExtendedJSON_Struct_Options decodes from the syntax
+// tree, which is used for the options json.Unmarshal does not support.
+func BenchmarkUnmarshal_ExtendedJSON_Struct_Options(b *testing.B) {
+	b.SetBytes(int64(len(benchExtendedData)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v struct{ Items []benchItem }
+		if err := Unmarshal(benchExtendedData, &v, DisallowUnknownFields()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,415 @@
+package extjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// Document is an extended JSON document which can be edited without
+// losing comments and formatting. Edits only change the text of the
+// edited values, everything else, e.g. comments, trailing commas, quoting
+// style and whitespace, is kept as in the original data.
+//
+// Paths used by Document are dot-separated object keys and array
+// indexes, e.g. "servers.0.port", a literal dot in key is escaped as
+// "\.". The empty path refers to the root value.
+type Document struct {
+	data []byte
+	opts parser.Options
+	root *parser.Node
+}
+
+// ParseDocument parses data as an extended JSON document.
+// Options which enable grammar modes, e.g. EnableJSON5, and Allow
+// are respected, directives are kept as is and are not evaluated.
+func ParseDocument(data []byte, options ...ExtOption) (*Document, error) {
+	opt := new(extOptions).apply(options...)
+	doc := &Document{
+		opts: parser.Options{
+			Disallowed: opt.Disallowed,
+			JSON5:      opt.JSON5,
+			Hjson:      opt.Hjson,
+			Python:     opt.Python,
+		},
+	}
+	if err := doc.reset(data); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func (d *Document) reset(data []byte) error {
+	tree, err := parser.ParseTree(data, d.opts)
+	if err != nil {
+		return err
+	}
+	d.data = data
+	d.root = tree.Root
+	return nil
+}
+
+// Bytes returns the current content of the document.
+// The returned slice must not be modified.
+func (d *Document) Bytes() []byte {
+	return d.data
+}
+
+// Set sets the value at path to value, which is encoded by encoding/json.
+// If the path does not exist and its parent is an object, the key is
+// added as the last member of the object.
+func (d *Document) Set(path string, value interface{}) error {
+	text, err := marshalValue(value)
+	if err != nil {
+		return err
+	}
+	keys := splitPath(path)
+	if len(keys) == 0 {
+		return d.apply(edit{d.root.Begin, d.root.End, text})
+	}
+	parent, err := d.find(path, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	key := keys[len(keys)-1]
+	if i := childIndex(parent, key); i >= 0 {
+		child := parent.Children[i]
+		return d.apply(edit{child.Begin, child.End, text})
+	}
+	if parent.Kind != parser.NodeObject {
+		return fmt.Errorf("path %q not found", path)
+	}
+	return d.insert(parent, len(parent.Children), d.memberText(parent, key, text))
+}
+
+// Delete deletes the object member or array element at path.
+// Comments on the same line and the line comments directly above the
+// deleted value are also deleted.
+func (d *Document) Delete(path string) error {
+	keys := splitPath(path)
+	if len(keys) == 0 {
+		return fmt.Errorf("cannot delete the root value")
+	}
+	parent, err := d.find(path, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	i := childIndex(parent, keys[len(keys)-1])
+	if i < 0 {
+		return fmt.Errorf("path %q not found", path)
+	}
+	return d.apply(d.deleteEdits(parent, i)...)
+}
+
+// Insert inserts value before the array element at path, the last
+// element of path must be an array index, which may be equal to the
+// length of the array to append value to the array.
+func (d *Document) Insert(path string, value interface{}) error {
+	text, err := marshalValue(value)
+	if err != nil {
+		return err
+	}
+	keys := splitPath(path)
+	if len(keys) == 0 {
+		return fmt.Errorf("cannot insert at the root value")
+	}
+	parent, err := d.find(path, keys[:len(keys)-1])
+	if err != nil {
+		return err
+	}
+	index, err := strconv.Atoi(keys[len(keys)-1])
+	if parent.Kind != parser.NodeArray || err != nil {
+		return fmt.Errorf("path %q is not an array index", path)
+	}
+	if index < 0 || index > len(parent.Children) {
+		return fmt.Errorf("path %q index out of range", path)
+	}
+	return d.insert(parent, index, text)
+}
+
+func (d *Document) find(path string, keys []string) (*parser.Node, error) {
+	node := d.root
+	for _, key := range keys {
+		i := childIndex(node, key)
+		if i < 0 {
+			return nil, fmt.Errorf("path %q not found", path)
+		}
+		node = node.Children[i]
+	}
+	return node, nil
+}
+
+// childIndex returns index of the child of node specified by key,
+// the last member is used if an object contains duplicate keys.
+func childIndex(node *parser.Node, key string) int {
+	switch node.Kind {
+	case parser.NodeObject:
+		for i := len(node.Children) - 1; i >= 0; i-- {
+			if node.Children[i].Key == key {
+				return i
+			}
+		}
+	case parser.NodeArray:
+		i, err := strconv.Atoi(key)
+		if err == nil && i >= 0 && i < len(node.Children) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (d *Document) insert(parent *parser.Node, index int, text string) error {
+	children := parent.Children
+	if len(children) == 0 {
+		if strings.IndexByte("{[(", d.data[parent.Begin]) < 0 {
+			return fmt.Errorf("cannot insert into value at offset %d", parent.Begin)
+		}
+		return d.apply(edit{parent.Begin + 1, parent.Begin + 1, text})
+	}
+
+	multiline := d.isMultiline(parent)
+	useComma := d.useComma(parent, multiline)
+	sep := " "
+	if index < len(children) {
+		begin := memberBegin(children[index])
+		if multiline {
+			sep = "\n" + d.indent(begin)
+		}
+		if useComma {
+			text += ","
+		}
+		return d.apply(edit{begin, begin, text + sep})
+	}
+
+	last := children[len(children)-1]
+	if multiline {
+		sep = "\n" + d.indent(memberBegin(last))
+	}
+	if last.Comma >= 0 {
+		// Keep the trailing comma.
+		pos := last.Comma + 1
+		if multiline {
+			pos = d.lineEnd(pos)
+		}
+		return d.apply(edit{pos, pos, sep + text + ","})
+	}
+	pos := last.End
+	if multiline {
+		pos = d.lineEnd(pos)
+	}
+	if !useComma {
+		return d.apply(edit{pos, pos, sep + text})
+	}
+	if pos == last.End {
+		return d.apply(edit{pos, pos, "," + sep + text})
+	}
+	return d.apply(edit{last.End, last.End, ","}, edit{pos, pos, sep + text})
+}
+
+func (d *Document) deleteEdits(parent *parser.Node, index int) []edit {
+	child := parent.Children[index]
+	begin, end := memberBegin(child), child.End
+	if child.Comma >= 0 {
+		end = child.Comma + 1
+	}
+
+	var edits []edit
+	lineBegin, ownLine := d.lineBegin(begin)
+	lineEnd := d.lineEnd(end)
+	if ownLine && lineEnd < len(d.data) && (d.data[lineEnd] == '\n' || d.data[lineEnd] == '\r') {
+		if bytes.HasPrefix(d.data[lineEnd:], []byte("\r\n")) {
+			lineEnd++
+		}
+		edits = append(edits, edit{d.leadingComments(lineBegin), lineEnd + 1, ""})
+		if child.Comma < 0 && index > 0 && parent.Children[index-1].Comma >= 0 {
+			comma := parent.Children[index-1].Comma
+			edits = append(edits, edit{comma, comma + 1, ""})
+		}
+		return edits
+	}
+
+	if last := index == len(parent.Children)-1; last && index > 0 && parent.Children[index-1].Comma >= 0 {
+		// Remove the comma before the value, or the space before it
+		// if the trailing comma is kept.
+		begin = parent.Children[index-1].Comma
+		if child.Comma >= 0 {
+			begin++
+		}
+	} else {
+		end = d.skipBlank(end)
+	}
+	return append(edits, edit{begin, end, ""})
+}
+
+func (d *Document) memberText(parent *parser.Node, key, value string) string {
+	quoted, _ := marshalValue(key)
+	colon := ": "
+	if n := len(parent.Children); n > 0 {
+		last := parent.Children[n-1]
+		if c := d.data[last.KeyBegin]; c != '"' && c != '\'' && isSimpleIdentifier(key) {
+			quoted = key
+		}
+		colon = string(d.data[last.KeyEnd:last.Begin])
+	}
+	return quoted + colon + value
+}
+
+// isMultiline reports whether children of parent are on separate lines
+// with the opening bracket.
+func (d *Document) isMultiline(parent *parser.Node) bool {
+	first := memberBegin(parent.Children[0])
+	return bytes.IndexByte(d.data[parent.Begin:first], '\n') >= 0
+}
+
+// useComma reports whether commas are used to separate children,
+// which may be omitted on separate lines in Hjson.
+func (d *Document) useComma(parent *parser.Node, multiline bool) bool {
+	if !d.opts.Hjson || !multiline {
+		return true
+	}
+	for _, child := range parent.Children {
+		if child.Comma >= 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// indent returns the whitespace before pos on the same line.
+func (d *Document) indent(pos int) string {
+	begin, ownLine := d.lineBegin(pos)
+	if !ownLine {
+		return ""
+	}
+	return string(d.data[begin:pos])
+}
+
+// lineBegin returns the beginning of the line which contains pos,
+// and reports whether there are only spaces before pos on the line.
+func (d *Document) lineBegin(pos int) (int, bool) {
+	i := pos
+	for i > 0 && (d.data[i-1] == ' ' || d.data[i-1] == '\t') {
+		i--
+	}
+	return i, i == 0 || d.data[i-1] == '\n'
+}
+
+// lineEnd skips blanks and a trailing comment after pos on the same
+// line, and returns the position of the line break or the next token.
+func (d *Document) lineEnd(pos int) int {
+	pos = d.skipBlank(pos)
+	rest := d.data[pos:]
+	switch {
+	case bytes.HasPrefix(rest, []byte("//")), bytes.HasPrefix(rest, []byte("#")):
+		if i := bytes.IndexAny(rest, "\r\n"); i >= 0 {
+			return pos + i
+		}
+		return len(d.data)
+	case bytes.HasPrefix(rest, []byte("/*")):
+		i := bytes.Index(rest, []byte("*/"))
+		if i >= 0 && bytes.IndexByte(rest[:i], '\n') < 0 {
+			return d.lineEnd(pos + i + 2)
+		}
+	}
+	return pos
+}
+
+// leadingComments returns the beginning of the line comments which are
+// directly above the line beginning at pos.
+func (d *Document) leadingComments(pos int) int {
+	for pos > 0 {
+		begin := bytes.LastIndexByte(d.data[:pos-1], '\n') + 1
+		line := bytes.TrimSpace(d.data[begin:pos])
+		if !bytes.HasPrefix(line, []byte("//")) && !bytes.HasPrefix(line, []byte("#")) {
+			break
+		}
+		pos = begin
+	}
+	return pos
+}
+
+func (d *Document) skipBlank(pos int) int {
+	for pos < len(d.data) && (d.data[pos] == ' ' || d.data[pos] == '\t') {
+		pos++
+	}
+	return pos
+}
+
+type edit struct {
+	begin, end int
+	text       string
+}
+
+func (d *Document) apply(edits ...edit) error {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].begin > edits[j].begin })
+	data := append([]byte(nil), d.data...)
+	for _, e := range edits {
+		data = append(data[:e.begin], append([]byte(e.text), data[e.end:]...)...)
+	}
+	return d.reset(data)
+}
+
+func memberBegin(node *parser.Node) int {
+	if node.KeyEnd > 0 {
+		return node.KeyBegin
+	}
+	return node.Begin
+}
+
+func marshalValue(v interface{}) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+func splitPath(path string) []string {
+	if path == "" {
+		return nil
+	}
+	var keys []string
+	var key []byte
+	for i := 0; i < len(path); i++ {
+		switch c := path[i]; {
+		case c == '\\' && i+1 < len(path):
+			i++
+			key = append(key, path[i])
+		case c == '.':
+			keys = append(keys, string(key))
+			key = key[:0]
+		default:
+			key = append(key, c)
+		}
+	}
+	return append(keys, string(key))
+}
+
+// appendPath appends key to path, which is the inverse of splitPath.
+func appendPath(path, key string) string {
+	if strings.ContainsAny(key, `.\`) {
+		key = strings.NewReplacer(`\`, `\\`, ".", `\.`).Replace(key)
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func isSimpleIdentifier(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || c == '$' ||
+			i > 0 && c >= '0' && c <= '9') {
+			return false
+		}
+	}
+	return s != ""
+}
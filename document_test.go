@@ -0,0 +1,229 @@
+package extjson
+
+import (
+	"testing"
+)
+
+var documentData = `{
+	// Server settings.
+	server: {
+		host: 'localhost', // host name
+		"port": 8080,
+	},
+	/* Enabled features. */
+	"features": ["a", "b", "c"],
+	"users": [
+		"alice",
+		"bob" // the last one
+	],
+	"env": @env("HOME"),
+}
+`
+
+func TestDocument(t *testing.T) {
+	testCases := []struct {
+		name string
+		edit func(doc *Document) error
+		want string
+	}{
+		{
+			name: "unchanged",
+			edit: func(doc *Document) error { return nil },
+			want: documentData,
+		},
+		{
+			name: "set value",
+			edit: func(doc *Document) error { return doc.Set("server.port", 9090) },
+			want: `{
+	// Server settings.
+	server: {
+		host: 'localhost', // host name
+		"port": 9090,
+	},
+	/* Enabled features. */
+	"features": ["a", "b", "c"],
+	"users": [
+		"alice",
+		"bob" // the last one
+	],
+	"env": @env("HOME"),
+}
+`,
+		},
+		{
+			name: "add member",
+			edit: func(doc *Document) error { return doc.Set("server.timeout", "3s") },
+			want: `{
+	// Server settings.
+	server: {
+		host: 'localhost', // host name
+		"port": 8080,
+		"timeout": "3s",
+	},
+	/* Enabled features. */
+	"features": ["a", "b", "c"],
+	"users": [
+		"alice",
+		"bob" // the last one
+	],
+	"env": @env("HOME"),
+}
+`,
+		},
+		{
+			name: "delete member",
+			edit: func(doc *Document) error { return doc.Delete("server") },
+			want: `{
+	/* Enabled features. */
+	"features": ["a", "b", "c"],
+	"users": [
+		"alice",
+		"bob" // the last one
+	],
+	"env": @env("HOME"),
+}
+`,
+		},
+		{
+			name: "delete single line",
+			edit: func(doc *Document) error {
+				if err := doc.Delete("features.0"); err != nil {
+					return err
+				}
+				return doc.Delete("features.1")
+			},
+			want: `{
+	// Server settings.
+	server: {
+		host: 'localhost', // host name
+		"port": 8080,
+	},
+	/* Enabled features. */
+	"features": ["b"],
+	"users": [
+		"alice",
+		"bob" // the last one
+	],
+	"env": @env("HOME"),
+}
+`,
+		},
+		{
+			name: "delete last element",
+			edit: func(doc *Document) error { return doc.Delete("users.1") },
+			want: `{
+	// Server settings.
+	server: {
+		host: 'localhost', // host name
+		"port": 8080,
+	},
+	/* Enabled features. */
+	"features": ["a", "b", "c"],
+	"users": [
+		"alice"
+	],
+	"env": @env("HOME"),
+}
+`,
+		},
+		{
+			name: "insert",
+			edit: func(doc *Document) error {
+				if err := doc.Insert("features.1", "x"); err != nil {
+					return err
+				}
+				if err := doc.Insert("users.0", "root"); err != nil {
+					return err
+				}
+				return doc.Insert("users.3", map[string]int{"id": 1})
+			},
+			want: `{
+	// Server settings.
+	server: {
+		host: 'localhost', // host name
+		"port": 8080,
+	},
+	/* Enabled features. */
+	"features": ["a", "x", "b", "c"],
+	"users": [
+		"root",
+		"alice",
+		"bob", // the last one
+		{"id":1}
+	],
+	"env": @env("HOME"),
+}
+`,
+		},
+	}
+	for _, tc := range testCases {
+		doc, err := ParseDocument([]byte(documentData))
+		if err != nil {
+			t.Fatalf("failed parse document: %v", err)
+		}
+		if err = tc.edit(doc); err != nil {
+			t.Errorf("%s: failed edit document: %v", tc.name, err)
+			continue
+		}
+		if got := string(doc.Bytes()); got != tc.want {
+			t.Errorf("%s: got =\n%s\nwant =\n%s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestDocumentEdit(t *testing.T) {
+	testCases := []struct {
+		data string
+		edit func(doc *Document) error
+		want string
+	}{
+		{`{}`, func(doc *Document) error { return doc.Set("a", 1) }, `{"a": 1}`},
+		{`[]`, func(doc *Document) error { return doc.Insert("0", true) }, `[true]`},
+		{`{a: 1}`, func(doc *Document) error { return doc.Set("b", nil) }, `{a: 1, b: null}`},
+		{`{a: 1}`, func(doc *Document) error { return doc.Set("b-c", 2) }, `{a: 1, "b-c": 2}`},
+		{`{"a":1}`, func(doc *Document) error { return doc.Set("b", 2) }, `{"a":1, "b":2}`},
+		{`{"a.b": 1}`, func(doc *Document) error { return doc.Set(`a\.b`, 2) }, `{"a.b": 2}`},
+		{`[1, 2, 3]`, func(doc *Document) error { return doc.Delete("1") }, `[1, 3]`},
+		{`[1, 2, 3,]`, func(doc *Document) error { return doc.Delete("2") }, `[1, 2,]`},
+		{`(1, 2)`, func(doc *Document) error { return doc.Set("1", "<&>") }, `(1, "<&>")`},
+		{`{"a": 1}`, func(doc *Document) error { return doc.Set("", []int{1}) }, `[1]`},
+	}
+	for _, tc := range testCases {
+		doc, err := ParseDocument([]byte(tc.data), EnablePython())
+		if err != nil {
+			t.Fatalf("failed parse document %s: %v", tc.data, err)
+		}
+		if err = tc.edit(doc); err != nil {
+			t.Errorf("failed edit document %s: %v", tc.data, err)
+			continue
+		}
+		if got := string(doc.Bytes()); got != tc.want {
+			t.Errorf("edit document %s: got = %s, want = %s", tc.data, got, tc.want)
+		}
+	}
+
+	hjson := "{\n  a: hello\n  b: 2\n}"
+	doc, err := ParseDocument([]byte(hjson), EnableHjson())
+	if err != nil {
+		t.Fatalf("failed parse Hjson document: %v", err)
+	}
+	if err = doc.Set("c", 3); err != nil {
+		t.Fatalf("failed edit Hjson document: %v", err)
+	}
+	if got, want := string(doc.Bytes()), "{\n  a: hello\n  b: 2\n  c: 3\n}"; got != want {
+		t.Errorf("edit Hjson document: got = %q, want = %q", got, want)
+	}
+
+	doc, _ = ParseDocument([]byte(`{"a": [1]}`))
+	for _, path := range []string{"b.c", "a.1", "a.x"} {
+		if err = doc.Delete(path); err == nil {
+			t.Errorf("expecting error deleting %s", path)
+		}
+	}
+	if err = doc.Insert("a.2", 1); err == nil {
+		t.Errorf("expecting index out of range error")
+	}
+	if err = doc.Insert("a", 1); err == nil {
+		t.Errorf("expecting not an array index error")
+	}
+}
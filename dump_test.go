@@ -0,0 +1,123 @@
+package extjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestDump(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.json")
+	v := map[string]interface{}{"b": "<&>", "a": []int{1, 2}}
+	if err := Dump(path, v, "", "  ", DumpSync()); err != nil {
+		t.Fatalf("failed dump: %v", err)
+	}
+	got, _ := os.ReadFile(path)
+	want := "{\n  \"a\": [\n    1,\n    2\n  ],\n  \"b\": \"<&>\"\n}\n"
+	if string(got) != want {
+		t.Errorf("dump: got = %q, want = %q", got, want)
+	}
+
+	if runtime.GOOS != "windows" {
+		if err := os.Chmod(path, 0600); err != nil {
+			t.Fatal(err)
+		}
+		if err := Dump(path, 1, "", ""); err != nil {
+			t.Fatalf("failed dump: %v", err)
+		}
+		fi, _ := os.Stat(path)
+		if fi.Mode().Perm() != 0600 {
+			t.Errorf("file mode is not preserved: %v", fi.Mode())
+		}
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temporary files are not removed: %v", entries)
+	}
+
+	if err := Dump(path, func() {}, "", ""); err == nil {
+		t.Errorf("expecting unsupported type error")
+	}
+	got, _ = os.ReadFile(path)
+	if string(got) != "1\n" {
+		t.Errorf("file is changed by failed dump: %q", got)
+	}
+	entries, _ = os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("temporary files are not removed: %v", entries)
+	}
+}
+
+func TestDumpTo(t *testing.T) {
+	type T struct {
+		B string `json:"b"`
+		A int    `json:"a"`
+	}
+	testCases := []struct {
+		opts []DumpOption
+		want string
+	}{
+		{nil, `{"b":"<&>","a":1}` + "\n"},
+		{[]DumpOption{DumpSortKeys()}, `{"a":1,"b":"<&>"}` + "\n"},
+		{[]DumpOption{DumpEscapeHTML()}, `{"b":"\u003c\u0026\u003e","a":1}` + "\n"},
+		{[]DumpOption{DumpIndent("", "\t")}, "{\n\t\"b\": \"<&>\",\n\t\"a\": 1\n}\n"},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		if err := DumpTo(&buf, T{B: "<&>", A: 1}, tc.opts...); err != nil {
+			t.Fatalf("failed dump: %v", err)
+		}
+		if buf.String() != tc.want {
+			t.Errorf("got = %q, want = %q", buf.String(), tc.want)
+		}
+	}
+}
+
+func TestDumpMarshaler(t *testing.T) {
+	type T struct {
+		B string `json:"b"`
+		A int    `json:"a"`
+	}
+	calls := 0
+	marshal := func(v interface{}) ([]byte, error) {
+		calls++
+		if _, ok := v.(func()); ok {
+			return nil, errors.New("unsupported value")
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		err := enc.Encode(v)
+		return bytes.TrimSpace(buf.Bytes()), err
+	}
+	testCases := []struct {
+		opts []DumpOption
+		want string
+	}{
+		{nil, `{"b":"<&>","a":1}` + "\n"},
+		{[]DumpOption{DumpIndent("", "  ")}, "{\n  \"b\": \"<&>\",\n  \"a\": 1\n}\n"},
+		{[]DumpOption{DumpSortKeys(), DumpEscapeHTML()}, `{"a":1,"b":"\u003c\u0026\u003e"}` + "\n"},
+	}
+	for _, tc := range testCases {
+		var buf bytes.Buffer
+		opts := append(tc.opts, DumpMarshaler(marshal))
+		if err := DumpTo(&buf, T{B: "<&>", A: 1}, opts...); err != nil {
+			t.Fatalf("failed dump: %v", err)
+		}
+		if buf.String() != tc.want {
+			t.Errorf("got = %q, want = %q", buf.String(), tc.want)
+		}
+	}
+	if calls != len(testCases) {
+		t.Errorf("marshal function is called %d times, want %d", calls, len(testCases))
+	}
+	err := DumpTo(new(bytes.Buffer), func() {}, DumpMarshaler(marshal))
+	if err == nil || err.Error() != "unsupported value" {
+		t.Errorf("expecting error of marshal function, got %v", err)
+	}
+}
@@ -0,0 +1,333 @@
+package extjson
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Encoder writes values as extended JSON to an output stream.
+//
+// Struct fields are encoded as encoding/json does, including the rules
+// for fields of embedded structs, and a comment can be attached to a
+// field by the "extjson" struct tag, e.g.
+//
+//	Port int `extjson:"port,comment=Listening port"`
+//
+// The name part of the tag is used as the key if the field has no name
+// specified by the "json" tag, since keys are matched case-insensitively
+// when decoding, it should only differ from the field name in case.
+// The comment option must be the last option of the tag, it may contain
+// commas, and "\n" starts a new comment line.
+//
+// Non-empty objects and arrays are written in multiple lines, the output
+// style, e.g. indentation, quoting and trailing commas, is specified by
+// FormatOptions.
+type Encoder struct {
+	w    io.Writer
+	opts FormatOptions
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer, opts FormatOptions) *Encoder {
+	return &Encoder{w: w, opts: opts}
+}
+
+// Encode writes the extended JSON encoding of v to the stream,
+// followed by a newline character.
+func (enc *Encoder) Encode(v interface{}) error {
+	e := &encodeState{}
+	if err := e.value(reflect.ValueOf(v)); err != nil {
+		return err
+	}
+	out, err := Format(e.Bytes(), enc.opts)
+	if err != nil {
+		return err
+	}
+	_, err = enc.w.Write(out)
+	return err
+}
+
+type encodeState struct {
+	bytes.Buffer
+
+	// visiting holds the pointers, maps and slices being encoded,
+	// to report cycles instead of recursing infinitely.
+	visiting map[visitKey]bool
+}
+
+type visitKey struct {
+	ptr uintptr
+	typ reflect.Type
+	len int
+}
+
+// enter marks v as being encoded until the returned function is called,
+// it returns an error if v is already being encoded.
+func (e *encodeState) enter(v reflect.Value) (leave func(), err error) {
+	key := visitKey{ptr: v.Pointer(), typ: v.Type()}
+	if v.Kind() == reflect.Slice {
+		key.len = v.Len()
+	}
+	if e.visiting[key] {
+		return nil, &json.UnsupportedValueError{Value: v, Str: fmt.Sprintf("encountered a cycle via %v", v.Type())}
+	}
+	if e.visiting == nil {
+		e.visiting = make(map[visitKey]bool)
+	}
+	e.visiting[key] = true
+	return func() { delete(e.visiting, key) }, nil
+}
+
+var (
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+func (e *encodeState) value(v reflect.Value) error {
+	if !v.IsValid() {
+		e.WriteString("null")
+		return nil
+	}
+	if v.Kind() != reflect.Ptr && v.CanAddr() && isMarshaler(reflect.PtrTo(v.Type())) {
+		return e.marshal(v.Addr())
+	}
+	if isMarshaler(v.Type()) {
+		return e.marshal(v)
+	}
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		if v.Kind() == reflect.Ptr {
+			leave, err := e.enter(v)
+			if err != nil {
+				return err
+			}
+			defer leave()
+		}
+		return e.value(v.Elem())
+	case reflect.Struct:
+		return e.structValue(v)
+	case reflect.Map:
+		if v.IsNil() {
+			e.WriteString("null")
+			return nil
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return err
+		}
+		defer leave()
+		return e.mapValue(v)
+	case reflect.Slice:
+		if v.IsNil() || v.Type().Elem().Kind() == reflect.Uint8 {
+			return e.marshal(v)
+		}
+		leave, err := e.enter(v)
+		if err != nil {
+			return err
+		}
+		defer leave()
+		return e.array(v)
+	case reflect.Array:
+		return e.array(v)
+	}
+	return e.marshal(v)
+}
+
+func isMarshaler(t reflect.Type) bool {
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType)
+}
+
+func (e *encodeState) marshal(v reflect.Value) error {
+	b, err := json.Marshal(v.Interface())
+	if err != nil {
+		return err
+	}
+	e.Write(b)
+	return nil
+}
+
+func (e *encodeState) array(v reflect.Value) error {
+	if v.Len() == 0 {
+		e.WriteString("[]")
+		return nil
+	}
+	e.WriteString("[\n")
+	for i := 0; i < v.Len(); i++ {
+		if err := e.value(v.Index(i)); err != nil {
+			return err
+		}
+		e.WriteString(",\n")
+	}
+	e.WriteString("]")
+	return nil
+}
+
+func (e *encodeState) mapValue(v reflect.Value) error {
+	type entry struct {
+		key   string
+		value reflect.Value
+	}
+	entries := make([]entry, 0, v.Len())
+	iter := v.MapRange()
+	for iter.Next() {
+		key, err := mapKey(iter.Key())
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{key, iter.Value()})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	e.WriteString("{\n")
+	for _, x := range entries {
+		if err := e.member(x.key, "", x.value); err != nil {
+			return err
+		}
+	}
+	e.WriteString("}")
+	return nil
+}
+
+// mapKey returns the JSON object key of the map key k, following the
+// rules of encoding/json.
+func mapKey(k reflect.Value) (string, error) {
+	if k.Kind() == reflect.String {
+		return k.String(), nil
+	}
+	if tm, ok := k.Interface().(encoding.TextMarshaler); ok {
+		if k.Kind() == reflect.Ptr && k.IsNil() {
+			return "", nil
+		}
+		b, err := tm.MarshalText()
+		return string(b), err
+	}
+	switch k.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(k.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(k.Uint(), 10), nil
+	}
+	return "", fmt.Errorf("unsupported map key type %v", k.Type())
+}
+
+func (e *encodeState) structValue(v reflect.Value) error {
+	begin := e.Len()
+	e.WriteString("{\n")
+	empty := true
+	fields := cachedDecodeFields(v.Type())
+	for i := range fields.list {
+		f := &fields.list[i]
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok || f.omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		if f.quoted && fv.Kind() == reflect.Ptr && !fv.IsNil() {
+			fv = fv.Elem()
+		}
+		if f.quoted && isQuotable(fv.Kind()) {
+			b, err := json.Marshal(fv.Interface())
+			if err != nil {
+				return err
+			}
+			fv = reflect.ValueOf(string(b))
+		}
+		name := f.name
+		if !f.tag && f.extName != "" {
+			name = f.extName
+		}
+		if err := e.member(name, f.comment, fv); err != nil {
+			return err
+		}
+		empty = false
+	}
+	if empty {
+		e.Truncate(begin)
+		e.WriteString("{}")
+		return nil
+	}
+	e.WriteString("}")
+	return nil
+}
+
+// isQuotable reports whether a value of kind k is encoded as string
+// with the ",string" option of the json tag.
+func isQuotable(k reflect.Kind) bool {
+	switch k {
+	case reflect.Bool, reflect.String,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (e *encodeState) member(key, comment string, v reflect.Value) error {
+	if comment != "" {
+		for _, line := range strings.Split(comment, "\n") {
+			e.WriteString("// " + line + "\n")
+		}
+	}
+	quoted, _ := marshalValue(key)
+	e.WriteString(quoted + ": ")
+	if err := e.value(v); err != nil {
+		return err
+	}
+	e.WriteString(",\n")
+	return nil
+}
+
+func parseExtTag(tag string) (name, comment string) {
+	name = tag
+	if i := strings.IndexByte(tag, ','); i >= 0 {
+		name = tag[:i]
+		if j := strings.Index(tag, ",comment="); j >= 0 {
+			comment = tag[j+len(",comment="):]
+		}
+	}
+	return name, strings.Replace(comment, `\n`, "\n", -1)
+}
+
+// fieldByIndex returns the nested field of v, it reports false if the
+// field is in a nil embedded struct pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package extjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testServerConfig struct {
+	Host    string        `json:"host" extjson:",comment=Host name, or IP address"`
+	Port    int           `extjson:"port,comment=Listening port"`
+	Timeout time.Duration `json:"timeout,string,omitempty"`
+	TLS     *struct {
+		Cert string `json:"cert"`
+	} `json:"tls,omitempty"`
+}
+
+type testConfig struct {
+	testServerConfig
+	Name    string            `json:"name" extjson:",comment=Service name\nmust be unique"`
+	Tags    []string          `json:"tags"`
+	Labels  map[string]string `json:"labels"`
+	Created time.Time         `json:"created"`
+	Extra   struct{}          `json:"extra"`
+	Ignored string            `json:"-"`
+	private int
+}
+
+func TestEncoder(t *testing.T) {
+	cfg := testConfig{
+		testServerConfig: testServerConfig{Host: "localhost", Port: 8080},
+		Name:             "demo",
+		Tags:             []string{"a", "it's"},
+		Labels:           map[string]string{"z": "1", "a": "2"},
+		Created:          time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Ignored:          "x",
+	}
+	want := `{
+	// Host name, or IP address
+	host: 'localhost',
+	// Listening port
+	port: 8080,
+	// Service name
+	// must be unique
+	name: 'demo',
+	tags: [
+		'a',
+		'it\'s',
+	],
+	labels: {
+		a: '2',
+		z: '1',
+	},
+	created: '2022-01-02T03:04:05Z',
+	extra: {},
+}
+`
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf, FormatOptions{Indent: "\t", SingleQuote: true, UnquoteKeys: true, TrailingComma: true})
+	if err := enc.Encode(cfg); err != nil {
+		t.Fatalf("failed encode: %v", err)
+	}
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+
+	var got testConfig
+	if err := Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("failed unmarshal encoded data: %v", err)
+	}
+	cfg.Ignored = ""
+	if !reflect.DeepEqual(got, cfg) {
+		t.Errorf("round trip: got = %+v, want = %+v", got, cfg)
+	}
+
+	buf.Reset()
+	cfg.Timeout = time.Second
+	if err := NewEncoder(&buf, FormatOptions{}).Encode(&cfg.testServerConfig); err != nil {
+		t.Fatalf("failed encode: %v", err)
+	}
+	want = "{\n  // Host name, or IP address\n  \"host\": \"localhost\",\n  // Listening port\n  \"port\": 8080,\n  \"timeout\": \"1000000000\"\n}\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestEncoderEmbeddedFields(t *testing.T) {
+	type A struct {
+		X int
+		Y int `json:"y"`
+	}
+	type B struct {
+		A
+		X int
+		Y int `json:"-"`
+		Z int `json:",string"`
+	}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, FormatOptions{}).Encode(B{A: A{X: 1, Y: 2}, X: 3, Z: 4}); err != nil {
+		t.Fatalf("failed encode: %v", err)
+	}
+	want := "{\n  \"y\": 2,\n  \"X\": 3,\n  \"Z\": \"4\"\n}\n"
+	if buf.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestEncoderCycle(t *testing.T) {
+	type node struct {
+		Next *node `json:"next"`
+	}
+	n := &node{}
+	n.Next = n
+	m := map[string]interface{}{}
+	m["m"] = m
+	s := []interface{}{nil}
+	s[0] = s
+	for _, v := range []interface{}{n, m, s} {
+		var buf bytes.Buffer
+		err := NewEncoder(&buf, FormatOptions{}).Encode(v)
+		if _, ok := err.(*json.UnsupportedValueError); !ok {
+			t.Errorf("encode %T: want cycle error, got %v", v, err)
+		}
+	}
+
+	// Shared values are not cycles.
+	shared := &node{}
+	var buf bytes.Buffer
+	if err := NewEncoder(&buf, FormatOptions{}).Encode([]*node{shared, shared}); err != nil {
+		t.Errorf("failed encode shared value: %v", err)
+	}
+}
+
+func TestEncoderExtName(t *testing.T) {
+	type config struct {
+		ListenPort int    `extjson:"listenPort,comment=Listening port"`
+		Name       string `json:"name" extjson:"ignored"`
+	}
+	cfg := config{ListenPort: 8080, Name: "demo"}
+	for _, opts := range []FormatOptions{{}, {UnquoteKeys: true, TrailingComma: true}} {
+		var buf bytes.Buffer
+		if err := NewEncoder(&buf, opts).Encode(cfg); err != nil {
+			t.Fatalf("failed encode: %v", err)
+		}
+		if !bytes.Contains(buf.Bytes(), []byte("listenPort")) || !bytes.Contains(buf.Bytes(), []byte("name")) {
+			t.Errorf("unexpected keys in output:\n%s", buf.String())
+		}
+		// The decoded value does not depend on how the data is decoded.
+		members := bytes.TrimRight(bytes.TrimSuffix(bytes.TrimSpace(buf.Bytes()), []byte("}")), ", \n")
+		withDirective := []byte(string(members) + `, "x": @fn("nowUnix")}`)
+		for _, data := range [][]byte{buf.Bytes(), withDirective} {
+			var got config
+			if err := Unmarshal(data, &got); err != nil {
+				t.Fatalf("failed unmarshal %s: %v", data, err)
+			}
+			if got != cfg {
+				t.Errorf("round trip %s: got = %+v, want = %+v", data, got, cfg)
+			}
+		}
+	}
+}
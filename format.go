@@ -0,0 +1,396 @@
+package extjson
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jxskiss/extjson/ast"
+)
+
+// FormatOptions configures Format.
+type FormatOptions struct {
+	// Indent is the indentation of nested values, the default is two spaces.
+	Indent string
+
+	// SingleQuote quotes strings and keys with single quotes instead of
+	// double quotes.
+	SingleQuote bool
+
+	// UnquoteKeys writes object keys which are simple identifiers
+	// without quotes.
+	UnquoteKeys bool
+
+	// TrailingComma adds a comma after the last member or element of
+	// multi-line objects and arrays.
+	TrailingComma bool
+
+	// SortKeys sorts object members by key, comments move together
+	// with the members they are attached to.
+	SortKeys bool
+
+	// JSON5, Hjson and Python enable the grammar modes to parse data,
+	// see EnableJSON5, EnableHjson and EnablePython.
+	JSON5  bool
+	Hjson  bool
+	Python bool
+}
+
+// Format returns the canonical format of the extended JSON data.
+//
+// Objects and arrays which are written in a single line are kept in a
+// single line, others are re-indented with one member or element per line.
+// Strings and keys are quoted as specified by opts, Python tuples and
+// sets are written as arrays, numbers, booleans, nulls, directives and
+// comments are kept verbatim. Trailing comments of consecutive lines
+// are aligned, and at most one blank line is kept between members.
+//
+// Formatting a formatted document again does not change it.
+func Format(data []byte, opts FormatOptions) ([]byte, error) {
+	var mode ast.Mode
+	if opts.JSON5 {
+		mode |= ast.JSON5
+	}
+	if opts.Hjson {
+		mode |= ast.Hjson
+	}
+	if opts.Python {
+		mode |= ast.Python
+	}
+	file, err := ast.Parse(data, mode)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Indent == "" {
+		opts.Indent = "  "
+	}
+	f := &formatter{opts: &opts, comments: file.Comments}
+	f.file(file)
+	return f.bytes(), nil
+}
+
+type formatter struct {
+	opts     *FormatOptions
+	comments []*ast.Comment
+	lines    []*fmtLine
+}
+
+type fmtLine struct {
+	depth   int
+	code    string
+	comment string
+}
+
+// fmtEntry is a member or an element of a container, with the comments
+// attached to it.
+type fmtEntry struct {
+	key      *ast.String
+	value    ast.Node
+	blank    bool // whether there is a blank line before the entry
+	leading  []fmtNote
+	inline   []*ast.Comment
+	trailing []*ast.Comment
+}
+
+type fmtNote struct {
+	comment *ast.Comment
+	blank   bool
+}
+
+func (f *formatter) file(file *ast.File) {
+	root := &fmtEntry{value: file.Value}
+	footer := f.attach([]*fmtEntry{root}, file.Span, 0)
+	first := true
+	f.notes(root.leading, 0, &first)
+	if root.blank && !first {
+		f.newline(0)
+	}
+	f.newline(0)
+	f.value(root.value, 0)
+	f.trailing(root.trailing)
+	f.notes(footer, 0, &first)
+}
+
+// attach attaches the comments in span, which are not inside the
+// entries, to the entries, and returns comments after the last entry.
+// line is the line where span begins.
+func (f *formatter) attach(entries []*fmtEntry, span ast.Span, line int) (footer []fmtNote) {
+	var notes []fmtNote
+	var prev *fmtEntry
+	next := 0
+	place := func() {
+		e := entries[next]
+		e.leading, notes = notes, nil
+		e.blank = entryPos(e).Line > line+1
+		line = e.value.End().Line
+		prev = e
+		next++
+	}
+	for _, c := range f.comments {
+		if c.Pos().Offset < span.Pos().Offset || c.End().Offset > span.End().Offset {
+			continue
+		}
+		for next < len(entries) && entries[next].value.End().Offset <= c.Pos().Offset {
+			place()
+		}
+		if next < len(entries) && entryPos(entries[next]).Offset < c.Pos().Offset {
+			// The comment is between the key and value of an object
+			// member, or inside the value which is handled when
+			// formatting the value.
+			e := entries[next]
+			if c.End().Offset <= e.value.Pos().Offset {
+				if isInlineComment(c) {
+					e.inline = append(e.inline, c)
+				} else {
+					notes = append(notes, fmtNote{comment: c})
+				}
+			}
+			continue
+		}
+		if prev != nil && len(notes) == 0 && c.Pos().Line == line {
+			prev.trailing = append(prev.trailing, c)
+		} else {
+			notes = append(notes, fmtNote{comment: c, blank: c.Pos().Line > line+1})
+		}
+		line = c.End().Line
+	}
+	for next < len(entries) {
+		place()
+	}
+	return notes
+}
+
+func entryPos(e *fmtEntry) ast.Position {
+	if e.key != nil {
+		return e.key.Pos()
+	}
+	return e.value.Pos()
+}
+
+func isInlineComment(c *ast.Comment) bool {
+	return strings.HasPrefix(c.Text, "/*") && !strings.Contains(c.Text, "\n")
+}
+
+func (f *formatter) hasComment(node ast.Node) bool {
+	for _, c := range f.comments {
+		if c.Pos().Offset >= node.Pos().Offset && c.End().Offset <= node.End().Offset {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *formatter) value(node ast.Node, depth int) {
+	switch n := node.(type) {
+	case *ast.Object:
+		entries := make([]*fmtEntry, len(n.Members))
+		for i, m := range n.Members {
+			entries[i] = &fmtEntry{key: m.Key, value: m.Value}
+		}
+		f.container(n, entries, "{", "}", depth)
+	case *ast.Array:
+		entries := make([]*fmtEntry, len(n.Elems))
+		for i, x := range n.Elems {
+			entries[i] = &fmtEntry{value: x}
+		}
+		f.container(n, entries, "[", "]", depth)
+	case *ast.String:
+		f.write(f.quote(n))
+	case *ast.Number:
+		f.write(n.Raw)
+	case *ast.Bool:
+		f.write(n.Raw)
+	case *ast.Null:
+		f.write(n.Raw)
+	case *ast.Directive:
+		f.write(f.directive(n))
+	}
+}
+
+func (f *formatter) container(node ast.Node, entries []*fmtEntry, open, close string, depth int) {
+	multiline := node.Pos().Line != node.End().Line || f.hasComment(node)
+	var footer []fmtNote
+	if multiline {
+		// Comments are attached by position, before sorting.
+		footer = f.attach(entries, ast.Span{Start: node.Pos(), Stop: node.End()}, node.Pos().Line)
+	}
+	if f.opts.SortKeys && len(entries) > 0 && entries[0].key != nil {
+		sort.SliceStable(entries, func(i, j int) bool {
+			return entries[i].key.Value < entries[j].key.Value
+		})
+	}
+	f.write(open)
+	if !multiline {
+		for i, e := range entries {
+			if i > 0 {
+				f.write(", ")
+			}
+			f.entry(e, depth)
+		}
+		f.write(close)
+		return
+	}
+
+	first := true
+	for i, e := range entries {
+		f.notes(e.leading, depth+1, &first)
+		if e.blank && !first {
+			f.newline(0)
+		}
+		f.newline(depth + 1)
+		first = false
+		f.entry(e, depth+1)
+		if i < len(entries)-1 || f.opts.TrailingComma {
+			f.write(",")
+		}
+		f.trailing(e.trailing)
+	}
+	f.notes(footer, depth+1, &first)
+	f.newline(depth)
+	f.write(close)
+}
+
+func (f *formatter) entry(e *fmtEntry, depth int) {
+	if e.key != nil {
+		f.write(f.key(e.key))
+		f.write(": ")
+		for _, c := range e.inline {
+			f.write(c.Text + " ")
+		}
+	}
+	f.value(e.value, depth)
+}
+
+// notes writes comments each in a line, blank lines before the first
+// line in a container are removed.
+func (f *formatter) notes(notes []fmtNote, depth int, first *bool) {
+	for _, note := range notes {
+		if note.blank && !*first {
+			f.newline(0)
+		}
+		f.newline(depth)
+		f.write(note.comment.Text)
+		*first = false
+	}
+}
+
+func (f *formatter) trailing(comments []*ast.Comment) {
+	for _, c := range comments {
+		line := f.lines[len(f.lines)-1]
+		if line.comment != "" {
+			line.comment += " "
+		}
+		line.comment += c.Text
+	}
+}
+
+func (f *formatter) write(s string) {
+	f.lines[len(f.lines)-1].code += s
+}
+
+func (f *formatter) newline(depth int) {
+	f.lines = append(f.lines, &fmtLine{depth: depth})
+}
+
+func (f *formatter) key(key *ast.String) string {
+	if f.opts.UnquoteKeys && isSimpleIdentifier(key.Value) {
+		switch key.Value {
+		case "True", "False", "None":
+		default:
+			return key.Value
+		}
+	}
+	return f.quoteString(key.Value)
+}
+
+func (f *formatter) quote(s *ast.String) string {
+	if s.Raw[0] == 'b' || s.Raw[0] == 'B' {
+		// Python bytes.
+		return s.Raw
+	}
+	return f.quoteString(s.Value)
+}
+
+func (f *formatter) quoteString(s string) string {
+	if !f.opts.SingleQuote {
+		quoted, _ := marshalValue(s)
+		return quoted
+	}
+	const hex = "0123456789abcdef"
+	var b strings.Builder
+	b.WriteByte('\'')
+	for _, r := range s {
+		switch r {
+		case '\'', '\\':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case '\t':
+			b.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				b.WriteString(`\u00`)
+				b.WriteByte(hex[r>>4])
+				b.WriteByte(hex[r&0xF])
+				continue
+			}
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+func (f *formatter) directive(d *ast.Directive) string {
+	return "@" + d.Name + "(" + d.Arg.Raw + ")"
+}
+
+func (f *formatter) bytes() []byte {
+	// Align trailing comments of consecutive lines with same depth.
+	widths := make([]int, len(f.lines))
+	for i := 0; i < len(f.lines); {
+		j := i
+		width := 0
+		for ; j < len(f.lines); j++ {
+			line := f.lines[j]
+			if line.comment == "" || line.depth != f.lines[i].depth || strings.Contains(line.code, "\n") {
+				break
+			}
+			if w := utf8.RuneCountInString(line.code); w > width {
+				width = w
+			}
+		}
+		for k := i; k < j; k++ {
+			widths[k] = width
+		}
+		if j == i {
+			j++
+		}
+		i = j
+	}
+
+	var buf strings.Builder
+	for i, line := range f.lines {
+		if line.code == "" && line.comment == "" {
+			buf.WriteByte('\n')
+			continue
+		}
+		buf.WriteString(strings.Repeat(f.opts.Indent, line.depth))
+		buf.WriteString(line.code)
+		if line.comment != "" {
+			if line.code != "" {
+				buf.WriteString(strings.Repeat(" ", widths[i]-utf8.RuneCountInString(line.code)+1))
+			}
+			buf.WriteString(line.comment)
+		}
+		buf.WriteByte('\n')
+	}
+	return []byte(buf.String())
+}
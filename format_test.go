@@ -0,0 +1,130 @@
+package extjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func readmeExample(t *testing.T) []byte {
+	readme, err := os.ReadFile("README.md")
+	if err != nil {
+		t.Fatalf("failed read README: %v", err)
+	}
+	const begin, end = "## Example\n\n```text\n", "```"
+	data := readme[bytes.Index(readme, []byte(begin))+len(begin):]
+	return data[:bytes.Index(data, []byte(end))]
+}
+
+func TestFormatIdempotent(t *testing.T) {
+	testdata, err := os.ReadFile("testdata.json")
+	if err != nil {
+		t.Fatalf("failed read testdata.json: %v", err)
+	}
+	inputs := map[string][]byte{
+		"testdata.json":  testdata,
+		"README example": readmeExample(t),
+		"malformed JSON": []byte(malformedJSONData),
+		"document":       []byte(documentData),
+	}
+	options := []FormatOptions{
+		{},
+		{Indent: "\t", TrailingComma: true},
+		{SingleQuote: true, UnquoteKeys: true},
+		{SortKeys: true},
+	}
+	for name, data := range inputs {
+		for _, opts := range options {
+			once, err := Format(data, opts)
+			if err != nil {
+				t.Errorf("%s: failed format: %v", name, err)
+				continue
+			}
+			twice, err := Format(once, opts)
+			if err != nil {
+				t.Errorf("%s: failed format formatted data: %v", name, err)
+				continue
+			}
+			if !bytes.Equal(once, twice) {
+				t.Errorf("%s: format is not idempotent with %+v:\n%s\n%s", name, opts, once, twice)
+			}
+		}
+	}
+
+	t.Setenv("SOME_ENV", "some-env-value")
+	for _, data := range [][]byte{testdata, []byte(malformedJSONData)} {
+		formatted, _ := Format(data, FormatOptions{SingleQuote: true, UnquoteKeys: true, SortKeys: true})
+		var want, got interface{}
+		if err = Unmarshal(data, &want, EnableEnv()); err != nil {
+			t.Fatalf("failed unmarshal data: %v", err)
+		}
+		if err = Unmarshal(formatted, &got, EnableEnv()); err != nil {
+			t.Fatalf("failed unmarshal formatted data: %v\n%s", err, formatted)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("formatted data changes value: got = %v, want = %v", got, want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	testCases := []struct {
+		data string
+		opts FormatOptions
+		want string
+	}{
+		{
+			data: `{"a":1,b:[1,2,{}],'c':"x"}`,
+			want: `{"a": 1, "b": [1, 2, {}], "c": "x"}` + "\n",
+		},
+		{
+			data: "// header\n\n{a: 1, /* b */ b: 2} // trailing\n// footer",
+			want: "// header\n\n{\n  \"a\": 1, /* b */\n  \"b\": 2\n} // trailing\n// footer\n",
+		},
+		{
+			data: "{\n\n\n  b: 'it\\'s', // b\n  a: \"x\\ny\", // a\n\n\n  c: /* c */ 3,\n}",
+			opts: FormatOptions{Indent: "\t", SingleQuote: true, UnquoteKeys: true},
+			want: "{\n\tb: 'it\\'s', // b\n\ta: 'x\\ny',  // a\n\n\tc: /* c */ 3\n}\n",
+		},
+		{
+			data: "{\n  // the b\n  b: 1,\n  a: [ // list\n    1, // one\n    22, // two\n  ],\n}",
+			opts: FormatOptions{SortKeys: true, TrailingComma: true},
+			want: "{\n  \"a\": [\n    // list\n    1,  // one\n    22, // two\n  ],\n  // the b\n  \"b\": 1,\n}\n",
+		},
+		{
+			data: `{b: 1, a: {d: 2, c: 3}}`,
+			opts: FormatOptions{SortKeys: true},
+			want: `{"a": {"c": 3, "d": 2}, "b": 1}` + "\n",
+		},
+		{
+			data: `{True: (1, 2), 'k': b'\x00', "s": set()}`,
+			opts: FormatOptions{UnquoteKeys: true, Python: true},
+			want: `{true: [1, 2], k: b'\x00', s: []}` + "\n",
+		},
+	}
+	for _, tc := range testCases {
+		got, err := Format([]byte(tc.data), tc.opts)
+		if err != nil {
+			t.Errorf("failed format %q: %v", tc.data, err)
+			continue
+		}
+		if string(got) != tc.want {
+			t.Errorf("format %q:\ngot  = %q\nwant = %q", tc.data, got, tc.want)
+		}
+	}
+
+	_, err := Format([]byte(`{a: 0x10}`), FormatOptions{})
+	if err == nil || !strings.Contains(err.Error(), "JSON5 mode is not enabled") {
+		t.Errorf("expecting JSON5 not enabled error, got %v", err)
+	}
+	if _, err = Format([]byte(`{a: 0x10}`), FormatOptions{JSON5: true}); err != nil {
+		t.Errorf("failed format JSON5: %v", err)
+	}
+	out, _ := Format([]byte(`["\u003c&\u003e"]`), FormatOptions{})
+	if !json.Valid(out) || string(out) != "[\"<&>\"]\n" {
+		t.Errorf("incorrect string quoting: %s", out)
+	}
+}
@@ -0,0 +1,74 @@
+package extjson
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// RegisterFunc registers a function process-wide, which then can be
+// used with the "@fn" directive without passing it by WithFuncMap.
+// Functions specified by WithFuncMap take precedence over registered ones.
+//
+// The name can be namespaced by dots, e.g. "myteam.lookup".
+// See FuncMap for the requirements of a function.
+//
+// It panics if name or fn is invalid. It is intended to be called
+// during program initialization.
+func RegisterFunc(name string, fn interface{}) {
+	if err := validateFunc(name, fn); err != nil {
+		panic("extjson: " + err.Error())
+	}
+	parser.RegisterFunc(name, fn)
+}
+
+// FuncInfo describes a function available to the "@fn" directive.
+type FuncInfo struct {
+	Name      string
+	Signature string
+	Builtin   bool // false if registered by RegisterFunc
+}
+
+// Builtins returns the builtin functions and functions registered by
+// RegisterFunc, sorted by name.
+func Builtins() []FuncInfo {
+	funcs := parser.Funcs()
+	out := make([]FuncInfo, 0, len(funcs))
+	for name, fn := range funcs {
+		out = append(out, FuncInfo{
+			Name:      name,
+			Signature: funcSignature(reflect.TypeOf(fn)),
+			Builtin:   parser.IsBuiltin(name),
+		})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+func funcSignature(typ reflect.Type) string {
+	typeString := func(t reflect.Type) string {
+		if t == funcContextType {
+			return "extjson.FuncContext"
+		}
+		return t.String()
+	}
+
+	var b strings.Builder
+	b.WriteString("func(")
+	for i := 0; i < typ.NumIn(); i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString(typeString(typ.In(i)))
+	}
+	b.WriteString(")")
+	switch typ.NumOut() {
+	case 1:
+		b.WriteString(" " + typeString(typ.Out(0)))
+	case 2:
+		b.WriteString(" (" + typeString(typ.Out(0)) + ", " + typeString(typ.Out(1)) + ")")
+	}
+	return b.String()
+}
@@ -2,7 +2,11 @@ package extjson
 
 import (
 	"encoding/json"
+	"fmt"
+	"strings"
 	"testing"
+
+	"github.com/jxskiss/extjson/internal/parser"
 )
 
 func TestBuiltinFunctions(t *testing.T) {
@@ -63,3 +67,136 @@ func TestUserFunctions(t *testing.T) {
 	tmp, _ := json.Marshal(got)
 	t.Log(string(tmp))
 }
+
+func TestFuncContext(t *testing.T) {
+	funcs := FuncMap{
+		"describe": func(ctx FuncContext, name string) string {
+			return fmt.Sprintf("%s@%v", name, ctx.Pos())
+		},
+		"double": func(ctx FuncContext, path string) (int, error) {
+			raw, err := ctx.Ref(path)
+			if err != nil {
+				return 0, err
+			}
+			var x int
+			err = json.Unmarshal(raw, &x)
+			return x * 2, err
+		},
+		"env": func(ctx FuncContext) string {
+			value, _ := ctx.LookupEnv("SOME_ENV")
+			return value
+		},
+	}
+	data := `{
+		value: 21,
+		describe: @fn("describe('abc')"),
+		double: @fn("double('value')"),
+		env: @fn("env"),
+	}`
+	t.Setenv("SOME_ENV", "some-env-value")
+	got := make(map[string]interface{})
+	err := Unmarshal([]byte(data), &got, WithFuncMap(funcs))
+	if err != nil {
+		t.Fatalf("failed unmarshal with function context, err= %v", err)
+	}
+	if x := got["describe"]; x != "abc@3:13" {
+		t.Fatalf("got unexpected result for describe: %q", x)
+	}
+	if x := got["double"]; x != float64(42) {
+		t.Fatalf("got unexpected result for double: %v", x)
+	}
+	if x := got["env"]; x != "" {
+		t.Fatalf("got unexpected result for env: %v", x)
+	}
+
+	got = make(map[string]interface{})
+	err = Unmarshal([]byte(data), &got, WithFuncMap(funcs), EnableEnv())
+	if err != nil {
+		t.Fatalf("failed unmarshal with env enabled, err= %v", err)
+	}
+	if x := got["env"]; x != "some-env-value" {
+		t.Fatalf("got unexpected result for env with env enabled: %v", x)
+	}
+
+	_, err = Clean([]byte(`{x: @fn("double('missing')")}`), WithFuncMap(funcs))
+	if err == nil || !strings.Contains(err.Error(), "cannot resolve reference missing") {
+		t.Fatalf("expecting reference error, got %v", err)
+	}
+
+	bad := FuncMap{"bad": func(x int, ctx FuncContext) int { return x }}
+	err = Unmarshal([]byte(`{x: @fn("bad(1)")}`), &got, WithFuncMap(bad))
+	if err == nil || !strings.Contains(err.Error(), "first argument") {
+		t.Fatalf("expecting invalid signature error, got %v", err)
+	}
+}
+
+func TestRegisterFunc(t *testing.T) {
+	RegisterFunc("test.upper", strings.ToUpper)
+	RegisterFunc("test.ns.repeat", func(s string, n int) string {
+		return strings.Repeat(s, n)
+	})
+
+	data := `{
+		upper: @fn("test.upper('abc')"),
+		repeat: @fn("test.ns.repeat('ab', 3)"),
+		local: @fn("test.local"),
+	}`
+	funcs := FuncMap{"test.local": func() string { return "local" }}
+	got := make(map[string]interface{})
+	err := Unmarshal([]byte(data), &got, WithFuncMap(funcs))
+	if err != nil {
+		t.Fatalf("failed unmarshal with registered functions, err= %v", err)
+	}
+	if got["upper"] != "ABC" || got["repeat"] != "ababab" || got["local"] != "local" {
+		t.Fatalf("got unexpected result: %v", got)
+	}
+
+	var found bool
+	for _, fn := range Builtins() {
+		switch fn.Name {
+		case "nowFormat":
+			if !fn.Builtin || fn.Signature != "func(string) string" {
+				t.Fatalf("got unexpected builtin info: %+v", fn)
+			}
+		case "test.ns.repeat":
+			found = true
+			if fn.Builtin || fn.Signature != "func(string, int) string" {
+				t.Fatalf("got unexpected registered info: %+v", fn)
+			}
+		}
+	}
+	if !found {
+		t.Fatalf("registered function not listed by Builtins")
+	}
+
+	// A registered function replaces the builtin of the same name.
+	builtinInfo := func(name string) FuncInfo {
+		for _, fn := range Builtins() {
+			if fn.Name == name {
+				return fn
+			}
+		}
+		t.Fatalf("function %s not listed by Builtins", name)
+		return FuncInfo{}
+	}
+	original := parser.Funcs()["randStr"]
+	RegisterFunc("randStr", func(n int) string { return strings.Repeat("x", n) })
+	if fn := builtinInfo("randStr"); fn.Builtin || fn.Signature != "func(int) string" {
+		t.Fatalf("got unexpected info of replaced builtin: %+v", fn)
+	}
+	RegisterFunc("randStr", original)
+	if fn := builtinInfo("randStr"); !fn.Builtin {
+		t.Fatalf("got unexpected info of restored builtin: %+v", fn)
+	}
+
+	for _, name := range []string{"", "1abc", "abc.", ".abc", "a..b", "a-b"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Fatalf("expecting panic for invalid name %q", name)
+				}
+			}()
+			RegisterFunc(name, strings.ToUpper)
+		}()
+	}
+}
@@ -0,0 +1,76 @@
+package extjson
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var hjsonData = `{
+  # specify rate in requests/second
+  rate: 1000
+  key-with-dash: 12 monkeys
+  text: look ma, no quotes! # not a comment
+  quoted: "value"
+  bool: true
+  notBool: true story
+  number: 3 // a comment
+  list: [
+    1
+    two
+    3, 4
+  ]
+  md:
+    '''
+    First line.
+      Indented line.
+    Last line.
+    '''
+  inline: '''single line'''
+  "empty": {}
+}`
+
+func TestHjson(t *testing.T) {
+	want := map[string]interface{}{
+		"rate":          float64(1000),
+		"key-with-dash": "12 monkeys",
+		"text":          "look ma, no quotes! # not a comment",
+		"quoted":        "value",
+		"bool":          true,
+		"notBool":       "true story",
+		"number":        float64(3),
+		"list":          []interface{}{float64(1), "two", float64(3), float64(4)},
+		"md":            "First line.\n  Indented line.\nLast line.",
+		"inline":        "single line",
+		"empty":         map[string]interface{}{},
+	}
+	got := make(map[string]interface{})
+	err := Unmarshal([]byte(hjsonData), &got, EnableHjson())
+	if err != nil {
+		t.Fatalf("failed unmarshal Hjson: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("expecting equal: got = %v, want = %v", got, want)
+	}
+
+	testCases := []struct {
+		data string
+		err  string
+	}{
+		{hjsonData, "Hjson syntax is not allowed at 4:3, Hjson mode is not enabled"},
+		{"{\"a\": 1\n\"b\": 2}", "Hjson syntax is not allowed at 2:1, Hjson mode is not enabled"},
+		{"[1\n  2]", "Hjson syntax is not allowed at 2:3, Hjson mode is not enabled"},
+		{"{\"a\": hello\n}", "parse error: unexpected 'h' at 1:7"},
+	}
+	for _, tc := range testCases {
+		_, err = Clean([]byte(tc.data))
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("got error %v, want %q", err, tc.err)
+		}
+	}
+
+	_, err = Clean([]byte(`{a: b}`), EnableHjson())
+	if err == nil || !strings.Contains(err.Error(), "parse error") {
+		t.Errorf("quoteless string should end at newline, got %v", err)
+	}
+}
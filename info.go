@@ -0,0 +1,137 @@
+package extjson
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// ParseInfo describes what a document depends on, which tells when it
+// needs to be loaded again, see LoadWithInfo and UnmarshalWithInfo.
+// Each entry is listed once, in the order it is first used.
+type ParseInfo struct {
+	// Files are the files included by "@incl", including files included
+	// by the included files.
+	Files []FileInfo
+
+	// Env are the environment variables read by "@env" and by functions
+	// which call FuncContext.LookupEnv.
+	Env []EnvInfo
+
+	// Refs are the references resolved by "@ref".
+	Refs []RefInfo
+
+	// Funcs are the functions called by "@fn".
+	Funcs []FuncCall
+}
+
+// FileInfo describes an included file.
+type FileInfo struct {
+	Path   string
+	SHA256 string // hex encoded SHA-256 hash of the content
+}
+
+// EnvInfo describes an environment variable which is read.
+type EnvInfo struct {
+	Name string
+	Set  bool // false if the variable is not present
+}
+
+// RefInfo describes a reference which is resolved.
+type RefInfo struct {
+	File string // path of the file which contains the reference
+	Path string
+}
+
+// FuncCall describes a function which is called.
+type FuncCall struct {
+	Name string
+
+	// Nondeterministic is true for builtin functions which return
+	// different results for each call, e.g. uuid, rand and nowUnix,
+	// thus the document is different each time it is loaded.
+	// It is false for functions which are not builtin, whose behavior
+	// is unknown.
+	Nondeterministic bool
+}
+
+// UnmarshalWithInfo is like Unmarshal, but it also returns what the
+// document depends on.
+// The info is returned even if an error occurs, then it describes the
+// part of the document which is parsed.
+func UnmarshalWithInfo(data []byte, v interface{}, options ...ExtOption) (*ParseInfo, error) {
+	info := new(ParseInfo)
+	opt := new(extOptions).apply(options...)
+	opt.info = info
+	return info, opt.unmarshal(context.Background(), data, v)
+}
+
+// LoadWithInfo is like Load, but it also returns what the document
+// depends on, see UnmarshalWithInfo.
+func LoadWithInfo(path string, v interface{}, options ...ExtOption) (*ParseInfo, error) {
+	info := new(ParseInfo)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return info, err
+	}
+	opt := new(extOptions).apply(options...)
+	opt.filePath = path
+	opt.info = info
+	return info, opt.unmarshal(context.Background(), data, v)
+}
+
+// record sets the callbacks of opts to record the info.
+func (info *ParseInfo) record(opts *parser.Options) {
+	onInclude := opts.OnInclude
+	opts.OnInclude = func(path string, data []byte) {
+		if onInclude != nil {
+			onInclude(path, data)
+		}
+		if data != nil {
+			info.addFile(path, data)
+		}
+	}
+	opts.OnEnv = info.addEnv
+	opts.OnRef = info.addRef
+	opts.OnFunc = info.addFunc
+}
+
+func (info *ParseInfo) addFile(path string, data []byte) {
+	for _, f := range info.Files {
+		if f.Path == path {
+			return
+		}
+	}
+	sum := sha256.Sum256(data)
+	info.Files = append(info.Files, FileInfo{Path: path, SHA256: hex.EncodeToString(sum[:])})
+}
+
+func (info *ParseInfo) addEnv(name string, set bool) {
+	for _, e := range info.Env {
+		if e.Name == name {
+			return
+		}
+	}
+	info.Env = append(info.Env, EnvInfo{Name: name, Set: set})
+}
+
+func (info *ParseInfo) addRef(file, path string) {
+	for _, r := range info.Refs {
+		if r.File == file && r.Path == path {
+			return
+		}
+	}
+	info.Refs = append(info.Refs, RefInfo{File: file, Path: path})
+}
+
+func (info *ParseInfo) addFunc(name string, nondeterministic bool) {
+	for _, f := range info.Funcs {
+		if f.Name == name {
+			return
+		}
+	}
+	info.Funcs = append(info.Funcs, FuncCall{Name: name, Nondeterministic: nondeterministic})
+}
@@ -0,0 +1,104 @@
+package extjson
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoadWithInfo(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.json": `{
+  name: @env("EXTJSON_INFO_SET"),
+  unset: @env("EXTJSON_INFO_UNSET"),
+  inc: @incl("inc.json"),
+  inc2: @incl("inc.json"),
+  copy: @ref("inc.a"),
+  id: @fn("uuid"),
+  n: @fn("randN(10)"),
+  region: @fn("region"),
+  home: @fn("home"),
+}`,
+		"inc.json":    `{a: @incl("nested.json"), b: @ref("a"), c: @ref("a"), t: @fn("nowUnix")}`,
+		"nested.json": `[1, 2]`,
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Setenv("EXTJSON_INFO_SET", "x")
+	defer os.Unsetenv("EXTJSON_INFO_SET")
+	os.Unsetenv("EXTJSON_INFO_UNSET")
+
+	funcs := FuncMap{
+		"region": func() string { return "local" },
+		"home": func(ctx FuncContext) string {
+			home, _ := ctx.LookupEnv("EXTJSON_INFO_HOME")
+			return home
+		},
+		// A function which replaces a builtin function is not known
+		// to be nondeterministic.
+		"randN": func(n int64) int64 { return n },
+	}
+	mainPath := filepath.Join(dir, "main.json")
+	var v map[string]interface{}
+	info, err := LoadWithInfo(mainPath, &v, EnableEnv(), IncludeRoot(dir), WithFuncMap(funcs))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash := func(name string) string {
+		sum := sha256.Sum256([]byte(files[name]))
+		return hex.EncodeToString(sum[:])
+	}
+	want := &ParseInfo{
+		Files: []FileInfo{
+			{Path: filepath.Join(dir, "inc.json"), SHA256: hash("inc.json")},
+			{Path: filepath.Join(dir, "nested.json"), SHA256: hash("nested.json")},
+		},
+		Env: []EnvInfo{
+			{Name: "EXTJSON_INFO_SET", Set: true},
+			{Name: "EXTJSON_INFO_UNSET", Set: false},
+			{Name: "EXTJSON_INFO_HOME", Set: false},
+		},
+		Refs: []RefInfo{
+			{File: filepath.Join(dir, "inc.json"), Path: "a"},
+			{File: mainPath, Path: "inc.a"},
+		},
+		Funcs: []FuncCall{
+			{Name: "nowUnix", Nondeterministic: true},
+			{Name: "uuid", Nondeterministic: true},
+			{Name: "randN", Nondeterministic: false},
+			{Name: "region", Nondeterministic: false},
+			{Name: "home", Nondeterministic: false},
+		},
+	}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("got info %+v\nwant %+v", info, want)
+	}
+	if v["name"] != "x" || v["n"] != float64(10) {
+		t.Errorf("got value %v", v)
+	}
+}
+
+func TestUnmarshalWithInfo(t *testing.T) {
+	var v interface{}
+	info, err := UnmarshalWithInfo([]byte(`{"a": 1}`), &v)
+	if err != nil || !reflect.DeepEqual(info, &ParseInfo{}) {
+		t.Errorf("got %+v, %v", info, err)
+	}
+
+	// The info of the parsed part is returned on errors.
+	info, err = UnmarshalWithInfo([]byte(`{a: @fn("uuid"), b: @incl("missing.json")}`), &v, IncludeRoot(t.TempDir()))
+	if err == nil {
+		t.Fatal("expecting error")
+	}
+	want := &ParseInfo{Funcs: []FuncCall{{Name: "uuid", Nondeterministic: true}}}
+	if !reflect.DeepEqual(info, want) {
+		t.Errorf("got info %+v, want %+v", info, want)
+	}
+}
@@ -1,8 +1,10 @@
 package parser
 
 import (
+	"context"
 	crand "crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	goast "go/ast"
@@ -10,12 +12,15 @@ import (
 	gotoken "go/token"
 	"io"
 	mrand "math/rand"
+	"os"
 	"reflect"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 	"unsafe"
+
+	"github.com/tidwall/gjson"
 )
 
 var (
@@ -23,24 +28,149 @@ var (
 	_rng  = mrand.New(mrand.NewSource(time.Now().UnixNano()))
 )
 
+var (
+	registryMu      sync.RWMutex
+	registeredFuncs = make(map[string]interface{})
+)
+
+// RegisterFunc registers fn to be available to all documents.
+// The caller is responsible to check that fn is a valid function.
+func RegisterFunc(name string, fn interface{}) {
+	registryMu.Lock()
+	registeredFuncs[name] = fn
+	registryMu.Unlock()
+}
+
+// Funcs returns the builtin and registered functions.
+// The returned map is a copy and can be modified by the caller.
+func Funcs() map[string]interface{} {
+	out := make(map[string]interface{}, len(builtinFuncs)+len(registeredFuncs))
+	for name, fn := range builtinFuncs {
+		out[name] = fn
+	}
+	registryMu.RLock()
+	for name, fn := range registeredFuncs {
+		out[name] = fn
+	}
+	registryMu.RUnlock()
+	return out
+}
+
+// IsBuiltin reports whether name is a builtin function, which is not
+// replaced by a different function registered with the same name.
+func IsBuiltin(name string) bool {
+	builtin, ok := builtinFuncs[name]
+	if !ok {
+		return false
+	}
+	registryMu.RLock()
+	fn, registered := registeredFuncs[name]
+	registryMu.RUnlock()
+	return !registered || reflect.ValueOf(fn).Pointer() == reflect.ValueOf(builtin).Pointer()
+}
+
 func (p *parser) addFuncs(funcMap map[string]interface{}) {
 	for name, fn := range builtinFuncs {
 		p.funcValMap[name] = reflect.ValueOf(fn)
 	}
+	registryMu.RLock()
+	for name, fn := range registeredFuncs {
+		p.funcValMap[name] = reflect.ValueOf(fn)
+	}
+	registryMu.RUnlock()
 	for name, fn := range funcMap {
 		p.funcValMap[name] = reflect.ValueOf(fn)
 	}
 }
 
-func (p *parser) callFunction(n *node32) (err error) {
-	n = n.up
-	str := p.parseString(n, false)
-	str = str[1 : len(str)-1]
+// FuncContext provides information about the document being parsed to
+// functions which are called by the "@fn" directive.
+type FuncContext interface {
+	// FilePath returns path of the file which contains the directive,
+	// it is empty if the data is not loaded from a file.
+	FilePath() string
+
+	// IncludeRoot returns the root directory to resolve included files.
+	IncludeRoot() string
+
+	// Pos returns position of the directive.
+	Pos() Position
+
+	// Ref returns the raw JSON value of the given gjson path.
+	// Only values which appear before the directive in the same file
+	// can be referenced.
+	Ref(path string) (json.RawMessage, error)
+
+	// LookupEnv retrieves the value of the environment variable named
+	// by key. It reports false if the variable is not present or the
+	// env feature is not enabled.
+	LookupEnv(key string) (string, bool)
+}
+
+var (
+	contextType     = reflect.TypeOf((*context.Context)(nil)).Elem()
+	funcContextType = reflect.TypeOf((*FuncContext)(nil)).Elem()
+)
+
+// leadingContextArgs reports whether the function accepts a
+// context.Context and a FuncContext as its leading arguments.
+// The context.Context, if present, must come first.
+func leadingContextArgs(typ reflect.Type) (hasCtx, hasFuncCtx bool) {
+	i := 0
+	if typ.NumIn() > i && typ.In(i) == contextType {
+		hasCtx = true
+		i++
+	}
+	if typ.NumIn() > i && typ.In(i) == funcContextType {
+		hasFuncCtx = true
+	}
+	return
+}
+
+type funcContext struct {
+	p   *parser
+	pos Position
+}
+
+func (c *funcContext) FilePath() string { return c.p.filePath }
+
+func (c *funcContext) IncludeRoot() string { return c.p.opts.IncludeRoot }
+
+func (c *funcContext) Pos() Position { return c.pos }
+
+func (c *funcContext) Ref(path string) (json.RawMessage, error) {
+	r := gjson.GetBytes(c.p.buf, path)
+	if !r.Exists() {
+		return nil, fmt.Errorf("cannot resolve reference %s", path)
+	}
+	if r.Index > 0 && containsRef(c.p.refs, r.Index, r.Index+len(r.Raw)) {
+		return nil, fmt.Errorf("reference %s contains unresolved reference", path)
+	}
+	return json.RawMessage(r.Raw), nil
+}
+
+func (c *funcContext) LookupEnv(key string) (string, bool) {
+	if !c.p.opts.EnableEnv {
+		return "", false
+	}
+	value, set := os.LookupEnv(key)
+	if c.p.opts.OnEnv != nil {
+		c.p.opts.OnEnv(key, set)
+	}
+	return value, set
+}
+
+func (p *parser) callFunction(d directive) (err error) {
+	pos := p.position(d.begin)
+	str, err := p.unquote(d.arg, d.argEnd)
+	if err != nil {
+		return err
+	}
 	str = strings.Replace(str, `'`, `"`, -1)
 
 	var funcName string
 	var fn reflect.Value
-	var callArgs []reflect.Value
+	var args []reflect.Value
 
 	if fn = p.funcValMap[str]; fn.IsValid() {
 		funcName = str
@@ -54,18 +184,32 @@ func (p *parser) callFunction(n *node32) (err error) {
 		if !fn.IsValid() {
 			return fmt.Errorf("function %s is unknown", expr.Func)
 		}
-		fnTyp := fn.Type()
-		if len(expr.Args) != fnTyp.NumIn() {
-			return fmt.Errorf("function %s arguments count not match", expr.Func)
-		}
 		funcName = expr.Func
-		for i := 0; i < len(expr.Args); i++ {
-			fnArgTyp := fnTyp.In(i)
-			if !expr.Args[i].Type().ConvertibleTo(fnArgTyp) {
-				return fmt.Errorf("function %s argument type not match: %v", expr.Func, expr.Args[i].Interface())
-			}
-			callArgs = append(callArgs, expr.Args[i].Convert(fnArgTyp))
+		args = expr.Args
+	}
+
+	if p.opts.OnFunc != nil {
+		p.opts.OnFunc(funcName, isNondeterministic(funcName, fn))
+	}
+
+	fnTyp := fn.Type()
+	var callArgs []reflect.Value
+	hasCtx, hasFuncCtx := leadingContextArgs(fnTyp)
+	if hasCtx {
+		callArgs = append(callArgs, reflect.ValueOf(p.opts.Context))
+	}
+	if hasFuncCtx {
+		callArgs = append(callArgs, reflect.ValueOf(&funcContext{p: p, pos: pos}))
+	}
+	if len(callArgs)+len(args) != fnTyp.NumIn() {
+		return fmt.Errorf("function %s arguments count not match", funcName)
+	}
+	for _, arg := range args {
+		fnArgTyp := fnTyp.In(len(callArgs))
+		if !arg.Type().ConvertibleTo(fnArgTyp) {
+			return fmt.Errorf("function %s argument type not match: %v", funcName, arg.Interface())
 		}
+		callArgs = append(callArgs, arg.Convert(fnArgTyp))
 	}
 
 	out := fn.Call(callArgs)
@@ -104,7 +248,10 @@ func parseExpression(str string) (*expression, error) {
 	if !ok {
 		return nil, errNotCallExpression
 	}
-	fnName := call.Fun.(*goast.Ident).String()
+	fnName, ok := funcName(call.Fun)
+	if !ok {
+		return nil, errNotCallExpression
+	}
 	var args []reflect.Value
 	for _, a := range call.Args {
 		lit, ok := a.(*goast.BasicLit)
@@ -130,6 +277,22 @@ func parseExpression(str string) (*expression, error) {
 	}, nil
 }
 
+// funcName returns the function name of a call expression,
+// a namespaced name is represented as selectors, e.g. "str.upper".
+func funcName(expr goast.Expr) (string, bool) {
+	switch x := expr.(type) {
+	case *goast.Ident:
+		return x.Name, true
+	case *goast.SelectorExpr:
+		ns, ok := funcName(x.X)
+		if !ok {
+			return "", false
+		}
+		return ns + "." + x.Sel.Name, true
+	}
+	return "", false
+}
+
 // -------- builtins -------- //
 
 var builtinFuncs = map[string]interface{}{
@@ -144,6 +307,26 @@ var builtinFuncs = map[string]interface{}{
 	"randStr":    builtinRandStr,
 }
 
+// nondeterministicFuncs are the builtin functions which return different
+// results for each call.
+var nondeterministicFuncs = map[string]bool{
+	"nowUnix":    true,
+	"nowMilli":   true,
+	"nowNano":    true,
+	"nowRFC3339": true,
+	"nowFormat":  true,
+	"uuid":       true,
+	"rand":       true,
+	"randN":      true,
+	"randStr":    true,
+}
+
+// isNondeterministic reports whether fn is a nondeterministic builtin
+// function, which is not replaced by a function of the same name.
+func isNondeterministic(name string, fn reflect.Value) bool {
+	return nondeterministicFuncs[name] && reflect.ValueOf(builtinFuncs[name]).Pointer() == fn.Pointer()
+}
+
 func builtinNowUnix() int64 {
 	return time.Now().Unix()
 }
@@ -0,0 +1,71 @@
+package parser
+
+import (
+	"strings"
+)
+
+func (p *parser) multiline(begin, end int) *Node {
+	p.use(FeatureHjson, begin)
+	p.pos = end
+	str := unquoteMultiline(p.data[begin+3:end-3], p.column(begin)-1)
+	p.buf = appendQuotedString(p.buf, str)
+	node := p.node(NodeString, begin, end)
+	if node != nil {
+		node.Value = str
+	}
+	return node
+}
+
+// quoteless parses a quoteless string, which ends at the line break,
+// trailing spaces are not included.
+func (p *parser) quoteless() *Node {
+	begin := p.pos
+	p.use(FeatureHjson, begin)
+	p.pos += lineLen(p.data[begin:])
+	end := p.pos
+	for end > begin && (p.data[end-1] == ' ' || p.data[end-1] == '\t') {
+		end--
+	}
+	p.buf = appendQuotedString(p.buf, b2s(p.data[begin:end]))
+	node := p.node(NodeString, begin, end)
+	if node != nil {
+		node.Value = string(p.data[begin:end])
+	}
+	return node
+}
+
+// unquoteMultiline decodes an Hjson multiline string, s is the content
+// between the triple quotes, and indent is the column of the opening
+// triple quotes, which determines the indentation to strip from
+// each line.
+func unquoteMultiline(s []byte, indent int) string {
+	skipIndent := func(i int) int {
+		for n := 0; n < indent && i < len(s) && s[i] <= ' ' && s[i] != '\n'; n++ {
+			i++
+		}
+		return i
+	}
+
+	i := 0
+	for i < len(s) && s[i] <= ' ' && s[i] != '\n' {
+		i++
+	}
+	if i < len(s) && s[i] == '\n' {
+		i = skipIndent(i + 1)
+	} else {
+		i = 0
+	}
+
+	var b strings.Builder
+	for ; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\r':
+		case '\n':
+			b.WriteByte('\n')
+			i = skipIndent(i+1) - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
@@ -0,0 +1,3865 @@
+package parser
+
+// Code generated by peg -output ./internal/parser/json.peg_test.go json.peg DO NOT EDIT.
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const endSymbol rune = 1114112
+
+/* The rule types inferred from the grammar are below. */
+type pegRule uint8
+
+const (
+	ruleUnknown pegRule = iota
+	ruleDocument
+	ruleJSON
+	ruleObject
+	ruleObjectKey
+	ruleNumberKey
+	ruleArray
+	ruleTuple
+	ruleSet
+	rulePySet
+	ruleKeyEnd
+	ruleNoComma
+	ruleDirective
+	ruleEnv
+	ruleInclude
+	ruleRefer
+	ruleFunc
+	ruleSimpleIdentifier
+	ruleIdentifier
+	ruleIdentifierEscape
+	ruleIdentifierUnicode
+	ruleString
+	ruleStringPrefix
+	ruleSingleQuoteLiteral
+	ruleDoubleQuoteLiteral
+	ruleSingleQuoteEscape
+	ruleDoubleQuoteEscape
+	ruleExtendedEscape
+	ruleLineContinuation
+	ruleUnicodeEscape
+	ruleLongUnicodeEscape
+	ruleHexEscape
+	ruleOctalEscape
+	ruleHexDigit
+	ruleMultilineString
+	ruleQuoteless
+	ruleQuotelessEnd
+	ruleQuotelessKey
+	ruleTrue
+	ruleFalse
+	ruleNull
+	ruleNumber
+	ruleMinus
+	rulePlus
+	ruleIntegralPart
+	ruleFractionalPart
+	ruleLeadingDecimal
+	ruleExponentPart
+	ruleHexNumber
+	ruleInfinity
+	ruleNaN
+	rulePyInfinity
+	rulePyNaN
+	ruleSpacing
+	ruleWhitespace
+	ruleExtendedSpace
+	ruleLongComment
+	ruleLineComment
+	rulePragma
+	ruleExtendedSpaceChar
+	ruleLWING
+	ruleRWING
+	ruleLBRK
+	ruleRBRK
+	ruleLPAR
+	ruleRPAR
+	ruleCOMMA
+	ruleCOLON
+	ruleEOT
+)
+
+var rul3s = [...]string{
+	"Unknown",
+	"Document",
+	"JSON",
+	"Object",
+	"ObjectKey",
+	"NumberKey",
+	"Array",
+	"Tuple",
+	"Set",
+	"PySet",
+	"KeyEnd",
+	"NoComma",
+	"Directive",
+	"Env",
+	"Include",
+	"Refer",
+	"Func",
+	"SimpleIdentifier",
+	"Identifier",
+	"IdentifierEscape",
+	"IdentifierUnicode",
+	"String",
+	"StringPrefix",
+	"SingleQuoteLiteral",
+	"DoubleQuoteLiteral",
+	"SingleQuoteEscape",
+	"DoubleQuoteEscape",
+	"ExtendedEscape",
+	"LineContinuation",
+	"UnicodeEscape",
+	"LongUnicodeEscape",
+	"HexEscape",
+	"OctalEscape",
+	"HexDigit",
+	"MultilineString",
+	"Quoteless",
+	"QuotelessEnd",
+	"QuotelessKey",
+	"True",
+	"False",
+	"Null",
+	"Number",
+	"Minus",
+	"Plus",
+	"IntegralPart",
+	"FractionalPart",
+	"LeadingDecimal",
+	"ExponentPart",
+	"HexNumber",
+	"Infinity",
+	"NaN",
+	"PyInfinity",
+	"PyNaN",
+	"Spacing",
+	"Whitespace",
+	"ExtendedSpace",
+	"LongComment",
+	"LineComment",
+	"Pragma",
+	"ExtendedSpaceChar",
+	"LWING",
+	"RWING",
+	"LBRK",
+	"RBRK",
+	"LPAR",
+	"RPAR",
+	"COMMA",
+	"COLON",
+	"EOT",
+}
+
+type token32 struct {
+	pegRule
+	begin, end uint32
+}
+
+func (t *token32) String() string {
+	return fmt.Sprintf("\x1B[34m%v\x1B[m %v %v", rul3s[t.pegRule], t.begin, t.end)
+}
+
+type node32 struct {
+	token32
+	up, next *node32
+}
+
+func (node *node32) print(w io.Writer, pretty bool, buffer string) {
+	var print func(node *node32, depth int)
+	print = func(node *node32, depth int) {
+		for node != nil {
+			for c := 0; c < depth; c++ {
+				fmt.Fprintf(w, " ")
+			}
+			rule := rul3s[node.pegRule]
+			quote := strconv.Quote(string(([]rune(buffer)[node.begin:node.end])))
+			if !pretty {
+				fmt.Fprintf(w, "%v %v\n", rule, quote)
+			} else {
+				fmt.Fprintf(w, "\x1B[36m%v\x1B[m %v\n", rule, quote)
+			}
+			if node.up != nil {
+				print(node.up, depth+1)
+			}
+			node = node.next
+		}
+	}
+	print(node, 0)
+}
+
+func (node *node32) Print(w io.Writer, buffer string) {
+	node.print(w, false, buffer)
+}
+
+func (node *node32) PrettyPrint(w io.Writer, buffer string) {
+	node.print(w, true, buffer)
+}
+
+type tokens32 struct {
+	tree []token32
+}
+
+func (t *tokens32) Trim(length uint32) {
+	t.tree = t.tree[:length]
+}
+
+func (t *tokens32) Print() {
+	for _, token := range t.tree {
+		fmt.Println(token.String())
+	}
+}
+
+func (t *tokens32) AST() *node32 {
+	type element struct {
+		node *node32
+		down *element
+	}
+	tokens := t.Tokens()
+	var stack *element
+	for _, token := range tokens {
+		if token.begin == token.end {
+			continue
+		}
+		node := &node32{token32: token}
+		for stack != nil && stack.node.begin >= token.begin && stack.node.end <= token.end {
+			stack.node.next = node.up
+			node.up = stack.node
+			stack = stack.down
+		}
+		stack = &element{node: node, down: stack}
+	}
+	if stack != nil {
+		return stack.node
+	}
+	return nil
+}
+
+func (t *tokens32) PrintSyntaxTree(buffer string) {
+	t.AST().Print(os.Stdout, buffer)
+}
+
+func (t *tokens32) WriteSyntaxTree(w io.Writer, buffer string) {
+	t.AST().Print(w, buffer)
+}
+
+func (t *tokens32) PrettyPrintSyntaxTree(buffer string) {
+	t.AST().PrettyPrint(os.Stdout, buffer)
+}
+
+func (t *tokens32) Add(rule pegRule, begin, end, index uint32) {
+	tree, i := t.tree, int(index)
+	if i >= len(tree) {
+		t.tree = append(tree, token32{pegRule: rule, begin: begin, end: end})
+		return
+	}
+	tree[i] = token32{pegRule: rule, begin: begin, end: end}
+}
+
+func (t *tokens32) Tokens() []token32 {
+	return t.tree
+}
+
+type JSON struct {
+	Buffer string
+	buffer []rune
+	rules  [69]func() bool
+	parse  func(rule ...int) error
+	reset  func()
+	Pretty bool
+	tokens32
+}
+
+func (p *JSON) Parse(rule ...int) error {
+	return p.parse(rule...)
+}
+
+func (p *JSON) Reset() {
+	p.reset()
+}
+
+type textPosition struct {
+	line, symbol int
+}
+
+type textPositionMap map[int]textPosition
+
+func translatePositions(buffer []rune, positions []int) textPositionMap {
+	length, translations, j, line, symbol := len(positions), make(textPositionMap, len(positions)), 0, 1, 0
+	sort.Ints(positions)
+
+search:
+	for i, c := range buffer {
+		if c == '\n' {
+			line, symbol = line+1, 0
+		} else {
+			symbol++
+		}
+		if i == positions[j] {
+			translations[positions[j]] = textPosition{line, symbol}
+			for j++; j < length; j++ {
+				if i != positions[j] {
+					continue search
+				}
+			}
+			break search
+		}
+	}
+
+	return translations
+}
+
+type parseError struct {
+	p   *JSON
+	max token32
+}
+
+func (e *parseError) Error() string {
+	tokens, err := []token32{e.max}, "\n"
+	positions, p := make([]int, 2*len(tokens)), 0
+	for _, token := range tokens {
+		positions[p], p = int(token.begin), p+1
+		positions[p], p = int(token.end), p+1
+	}
+	translations := translatePositions(e.p.buffer, positions)
+	format := "parse error near %v (line %v symbol %v - line %v symbol %v):\n%v\n"
+	if e.p.Pretty {
+		format = "parse error near \x1B[34m%v\x1B[m (line %v symbol %v - line %v symbol %v):\n%v\n"
+	}
+	for _, token := range tokens {
+		begin, end := int(token.begin), int(token.end)
+		err += fmt.Sprintf(format,
+			rul3s[token.pegRule],
+			translations[begin].line, translations[begin].symbol,
+			translations[end].line, translations[end].symbol,
+			strconv.Quote(string(e.p.buffer[begin:end])))
+	}
+
+	return err
+}
+
+func (p *JSON) PrintSyntaxTree() {
+	if p.Pretty {
+		p.tokens32.PrettyPrintSyntaxTree(p.Buffer)
+	} else {
+		p.tokens32.PrintSyntaxTree(p.Buffer)
+	}
+}
+
+func (p *JSON) WriteSyntaxTree(w io.Writer) {
+	p.tokens32.WriteSyntaxTree(w, p.Buffer)
+}
+
+func (p *JSON) SprintSyntaxTree() string {
+	var bldr strings.Builder
+	p.WriteSyntaxTree(&bldr)
+	return bldr.String()
+}
+
+func Pretty(pretty bool) func(*JSON) error {
+	return func(p *JSON) error {
+		p.Pretty = pretty
+		return nil
+	}
+}
+
+func Size(size int) func(*JSON) error {
+	return func(p *JSON) error {
+		p.tokens32 = tokens32{tree: make([]token32, 0, size)}
+		return nil
+	}
+}
+func (p *JSON) Init(options ...func(*JSON) error) error {
+	var (
+		max                  token32
+		position, tokenIndex uint32
+		buffer               []rune
+	)
+	for _, option := range options {
+		err := option(p)
+		if err != nil {
+			return err
+		}
+	}
+	p.reset = func() {
+		max = token32{}
+		position, tokenIndex = 0, 0
+
+		p.buffer = []rune(p.Buffer)
+		if len(p.buffer) == 0 || p.buffer[len(p.buffer)-1] != endSymbol {
+			p.buffer = append(p.buffer, endSymbol)
+		}
+		buffer = p.buffer
+	}
+	p.reset()
+
+	_rules := p.rules
+	tree := p.tokens32
+	p.parse = func(rule ...int) error {
+		r := 1
+		if len(rule) > 0 {
+			r = rule[0]
+		}
+		matches := p.rules[r]()
+		p.tokens32 = tree
+		if matches {
+			p.Trim(tokenIndex)
+			return nil
+		}
+		return &parseError{p, max}
+	}
+
+	add := func(rule pegRule, begin uint32) {
+		tree.Add(rule, begin, position, tokenIndex)
+		tokenIndex++
+		if begin != position && position > max.end {
+			max = token32{rule, begin, position}
+		}
+	}
+
+	matchDot := func() bool {
+		if buffer[position] != endSymbol {
+			position++
+			return true
+		}
+		return false
+	}
+
+	/*matchChar := func(c byte) bool {
+		if buffer[position] == c {
+			position++
+			return true
+		}
+		return false
+	}*/
+
+	/*matchRange := func(lower byte, upper byte) bool {
+		if c := buffer[position]; c >= lower && c <= upper {
+			position++
+			return true
+		}
+		return false
+	}*/
+
+	_rules = [...]func() bool{
+		nil,
+		/* 0 Document <- <(Spacing JSON EOT)> */
+		func() bool {
+			position0, tokenIndex0 := position, tokenIndex
+			{
+				position1 := position
+				if !_rules[ruleSpacing]() {
+					goto l0
+				}
+				if !_rules[ruleJSON]() {
+					goto l0
+				}
+				if !_rules[ruleEOT]() {
+					goto l0
+				}
+				add(ruleDocument, position1)
+			}
+			return true
+		l0:
+			position, tokenIndex = position0, tokenIndex0
+			return false
+		},
+		/* 1 JSON <- <((Object / Set / Array / Tuple / MultilineString / String / Directive / PySet / Quoteless / True / False / Null / Number) Spacing)> */
+		func() bool {
+			position2, tokenIndex2 := position, tokenIndex
+			{
+				position3 := position
+				{
+					position4, tokenIndex4 := position, tokenIndex
+					if !_rules[ruleObject]() {
+						goto l5
+					}
+					goto l4
+				l5:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleSet]() {
+						goto l6
+					}
+					goto l4
+				l6:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleArray]() {
+						goto l7
+					}
+					goto l4
+				l7:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleTuple]() {
+						goto l8
+					}
+					goto l4
+				l8:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleMultilineString]() {
+						goto l9
+					}
+					goto l4
+				l9:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleString]() {
+						goto l10
+					}
+					goto l4
+				l10:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleDirective]() {
+						goto l11
+					}
+					goto l4
+				l11:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[rulePySet]() {
+						goto l12
+					}
+					goto l4
+				l12:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleQuoteless]() {
+						goto l13
+					}
+					goto l4
+				l13:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleTrue]() {
+						goto l14
+					}
+					goto l4
+				l14:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleFalse]() {
+						goto l15
+					}
+					goto l4
+				l15:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleNull]() {
+						goto l16
+					}
+					goto l4
+				l16:
+					position, tokenIndex = position4, tokenIndex4
+					if !_rules[ruleNumber]() {
+						goto l2
+					}
+				}
+			l4:
+				if !_rules[ruleSpacing]() {
+					goto l2
+				}
+				add(ruleJSON, position3)
+			}
+			return true
+		l2:
+			position, tokenIndex = position2, tokenIndex2
+			return false
+		},
+		/* 2 Object <- <(LWING (ObjectKey COLON JSON (COMMA / NoComma))* RWING)> */
+		func() bool {
+			position17, tokenIndex17 := position, tokenIndex
+			{
+				position18 := position
+				if !_rules[ruleLWING]() {
+					goto l17
+				}
+			l19:
+				{
+					position20, tokenIndex20 := position, tokenIndex
+					if !_rules[ruleObjectKey]() {
+						goto l20
+					}
+					if !_rules[ruleCOLON]() {
+						goto l20
+					}
+					if !_rules[ruleJSON]() {
+						goto l20
+					}
+					{
+						position21, tokenIndex21 := position, tokenIndex
+						if !_rules[ruleCOMMA]() {
+							goto l22
+						}
+						goto l21
+					l22:
+						position, tokenIndex = position21, tokenIndex21
+						if !_rules[ruleNoComma]() {
+							goto l20
+						}
+					}
+				l21:
+					goto l19
+				l20:
+					position, tokenIndex = position20, tokenIndex20
+				}
+				if !_rules[ruleRWING]() {
+					goto l17
+				}
+				add(ruleObject, position18)
+			}
+			return true
+		l17:
+			position, tokenIndex = position17, tokenIndex17
+			return false
+		},
+		/* 3 ObjectKey <- <((String / (Identifier &KeyEnd) / (SimpleIdentifier &KeyEnd) / (NumberKey &KeyEnd) / QuotelessKey) Spacing)> */
+		func() bool {
+			position23, tokenIndex23 := position, tokenIndex
+			{
+				position24 := position
+				{
+					position25, tokenIndex25 := position, tokenIndex
+					if !_rules[ruleString]() {
+						goto l26
+					}
+					goto l25
+				l26:
+					position, tokenIndex = position25, tokenIndex25
+					if !_rules[ruleIdentifier]() {
+						goto l27
+					}
+					{
+						position28, tokenIndex28 := position, tokenIndex
+						if !_rules[ruleKeyEnd]() {
+							goto l27
+						}
+						position, tokenIndex = position28, tokenIndex28
+					}
+					goto l25
+				l27:
+					position, tokenIndex = position25, tokenIndex25
+					if !_rules[ruleSimpleIdentifier]() {
+						goto l29
+					}
+					{
+						position30, tokenIndex30 := position, tokenIndex
+						if !_rules[ruleKeyEnd]() {
+							goto l29
+						}
+						position, tokenIndex = position30, tokenIndex30
+					}
+					goto l25
+				l29:
+					position, tokenIndex = position25, tokenIndex25
+					if !_rules[ruleNumberKey]() {
+						goto l31
+					}
+					{
+						position32, tokenIndex32 := position, tokenIndex
+						if !_rules[ruleKeyEnd]() {
+							goto l31
+						}
+						position, tokenIndex = position32, tokenIndex32
+					}
+					goto l25
+				l31:
+					position, tokenIndex = position25, tokenIndex25
+					if !_rules[ruleQuotelessKey]() {
+						goto l23
+					}
+				}
+			l25:
+				if !_rules[ruleSpacing]() {
+					goto l23
+				}
+				add(ruleObjectKey, position24)
+			}
+			return true
+		l23:
+			position, tokenIndex = position23, tokenIndex23
+			return false
+		},
+		/* 4 NumberKey <- <Number> */
+		func() bool {
+			position33, tokenIndex33 := position, tokenIndex
+			{
+				position34 := position
+				if !_rules[ruleNumber]() {
+					goto l33
+				}
+				add(ruleNumberKey, position34)
+			}
+			return true
+		l33:
+			position, tokenIndex = position33, tokenIndex33
+			return false
+		},
+		/* 5 Array <- <(LBRK (JSON (COMMA / NoComma))* RBRK)> */
+		func() bool {
+			position35, tokenIndex35 := position, tokenIndex
+			{
+				position36 := position
+				if !_rules[ruleLBRK]() {
+					goto l35
+				}
+			l37:
+				{
+					position38, tokenIndex38 := position, tokenIndex
+					if !_rules[ruleJSON]() {
+						goto l38
+					}
+					{
+						position39, tokenIndex39 := position, tokenIndex
+						if !_rules[ruleCOMMA]() {
+							goto l40
+						}
+						goto l39
+					l40:
+						position, tokenIndex = position39, tokenIndex39
+						if !_rules[ruleNoComma]() {
+							goto l38
+						}
+					}
+				l39:
+					goto l37
+				l38:
+					position, tokenIndex = position38, tokenIndex38
+				}
+				if !_rules[ruleRBRK]() {
+					goto l35
+				}
+				add(ruleArray, position36)
+			}
+			return true
+		l35:
+			position, tokenIndex = position35, tokenIndex35
+			return false
+		},
+		/* 6 Tuple <- <(LPAR (JSON COMMA)* JSON? RPAR)> */
+		func() bool {
+			position41, tokenIndex41 := position, tokenIndex
+			{
+				position42 := position
+				if !_rules[ruleLPAR]() {
+					goto l41
+				}
+			l43:
+				{
+					position44, tokenIndex44 := position, tokenIndex
+					if !_rules[ruleJSON]() {
+						goto l44
+					}
+					if !_rules[ruleCOMMA]() {
+						goto l44
+					}
+					goto l43
+				l44:
+					position, tokenIndex = position44, tokenIndex44
+				}
+				{
+					position45, tokenIndex45 := position, tokenIndex
+					if !_rules[ruleJSON]() {
+						goto l45
+					}
+					goto l46
+				l45:
+					position, tokenIndex = position45, tokenIndex45
+				}
+			l46:
+				if !_rules[ruleRPAR]() {
+					goto l41
+				}
+				add(ruleTuple, position42)
+			}
+			return true
+		l41:
+			position, tokenIndex = position41, tokenIndex41
+			return false
+		},
+		/* 7 Set <- <(LWING JSON (COMMA JSON)* COMMA? RWING)> */
+		func() bool {
+			position47, tokenIndex47 := position, tokenIndex
+			{
+				position48 := position
+				if !_rules[ruleLWING]() {
+					goto l47
+				}
+				if !_rules[ruleJSON]() {
+					goto l47
+				}
+			l49:
+				{
+					position50, tokenIndex50 := position, tokenIndex
+					if !_rules[ruleCOMMA]() {
+						goto l50
+					}
+					if !_rules[ruleJSON]() {
+						goto l50
+					}
+					goto l49
+				l50:
+					position, tokenIndex = position50, tokenIndex50
+				}
+				{
+					position51, tokenIndex51 := position, tokenIndex
+					if !_rules[ruleCOMMA]() {
+						goto l51
+					}
+					goto l52
+				l51:
+					position, tokenIndex = position51, tokenIndex51
+				}
+			l52:
+				if !_rules[ruleRWING]() {
+					goto l47
+				}
+				add(ruleSet, position48)
+			}
+			return true
+		l47:
+			position, tokenIndex = position47, tokenIndex47
+			return false
+		},
+		/* 8 PySet <- <(('s' 'e' 't' '(' ')') / ('f' 'r' 'o' 'z' 'e' 'n' 's' 'e' 't' '(' Spacing Set? ')'))> */
+		func() bool {
+			position53, tokenIndex53 := position, tokenIndex
+			{
+				position54 := position
+				{
+					position55, tokenIndex55 := position, tokenIndex
+					if buffer[position] != rune('s') {
+						goto l56
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l56
+					}
+					position++
+					if buffer[position] != rune('t') {
+						goto l56
+					}
+					position++
+					if buffer[position] != rune('(') {
+						goto l56
+					}
+					position++
+					if buffer[position] != rune(')') {
+						goto l56
+					}
+					position++
+					goto l55
+				l56:
+					position, tokenIndex = position55, tokenIndex55
+					if buffer[position] != rune('f') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('r') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('o') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('z') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('n') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('s') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('t') {
+						goto l53
+					}
+					position++
+					if buffer[position] != rune('(') {
+						goto l53
+					}
+					position++
+					if !_rules[ruleSpacing]() {
+						goto l53
+					}
+					{
+						position57, tokenIndex57 := position, tokenIndex
+						if !_rules[ruleSet]() {
+							goto l57
+						}
+						goto l58
+					l57:
+						position, tokenIndex = position57, tokenIndex57
+					}
+				l58:
+					if buffer[position] != rune(')') {
+						goto l53
+					}
+					position++
+				}
+			l55:
+				add(rulePySet, position54)
+			}
+			return true
+		l53:
+			position, tokenIndex = position53, tokenIndex53
+			return false
+		},
+		/* 9 KeyEnd <- <(Spacing ':')> */
+		func() bool {
+			position59, tokenIndex59 := position, tokenIndex
+			{
+				position60 := position
+				if !_rules[ruleSpacing]() {
+					goto l59
+				}
+				if buffer[position] != rune(':') {
+					goto l59
+				}
+				position++
+				add(ruleKeyEnd, position60)
+			}
+			return true
+		l59:
+			position, tokenIndex = position59, tokenIndex59
+			return false
+		},
+		/* 10 NoComma <- <!','> */
+		func() bool {
+			position61, tokenIndex61 := position, tokenIndex
+			{
+				position62 := position
+				{
+					position63, tokenIndex63 := position, tokenIndex
+					if buffer[position] != rune(',') {
+						goto l63
+					}
+					position++
+					goto l61
+				l63:
+					position, tokenIndex = position63, tokenIndex63
+				}
+				add(ruleNoComma, position62)
+			}
+			return true
+		l61:
+			position, tokenIndex = position61, tokenIndex61
+			return false
+		},
+		/* 11 Directive <- <(Env / Include / Refer / Func)> */
+		func() bool {
+			position64, tokenIndex64 := position, tokenIndex
+			{
+				position65 := position
+				{
+					position66, tokenIndex66 := position, tokenIndex
+					if !_rules[ruleEnv]() {
+						goto l67
+					}
+					goto l66
+				l67:
+					position, tokenIndex = position66, tokenIndex66
+					if !_rules[ruleInclude]() {
+						goto l68
+					}
+					goto l66
+				l68:
+					position, tokenIndex = position66, tokenIndex66
+					if !_rules[ruleRefer]() {
+						goto l69
+					}
+					goto l66
+				l69:
+					position, tokenIndex = position66, tokenIndex66
+					if !_rules[ruleFunc]() {
+						goto l64
+					}
+				}
+			l66:
+				add(ruleDirective, position65)
+			}
+			return true
+		l64:
+			position, tokenIndex = position64, tokenIndex64
+			return false
+		},
+		/* 12 Env <- <('@' 'e' 'n' 'v' '(' String ')')> */
+		func() bool {
+			position70, tokenIndex70 := position, tokenIndex
+			{
+				position71 := position
+				if buffer[position] != rune('@') {
+					goto l70
+				}
+				position++
+				if buffer[position] != rune('e') {
+					goto l70
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l70
+				}
+				position++
+				if buffer[position] != rune('v') {
+					goto l70
+				}
+				position++
+				if buffer[position] != rune('(') {
+					goto l70
+				}
+				position++
+				if !_rules[ruleString]() {
+					goto l70
+				}
+				if buffer[position] != rune(')') {
+					goto l70
+				}
+				position++
+				add(ruleEnv, position71)
+			}
+			return true
+		l70:
+			position, tokenIndex = position70, tokenIndex70
+			return false
+		},
+		/* 13 Include <- <('@' 'i' 'n' 'c' 'l' '(' String ')')> */
+		func() bool {
+			position72, tokenIndex72 := position, tokenIndex
+			{
+				position73 := position
+				if buffer[position] != rune('@') {
+					goto l72
+				}
+				position++
+				if buffer[position] != rune('i') {
+					goto l72
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l72
+				}
+				position++
+				if buffer[position] != rune('c') {
+					goto l72
+				}
+				position++
+				if buffer[position] != rune('l') {
+					goto l72
+				}
+				position++
+				if buffer[position] != rune('(') {
+					goto l72
+				}
+				position++
+				if !_rules[ruleString]() {
+					goto l72
+				}
+				if buffer[position] != rune(')') {
+					goto l72
+				}
+				position++
+				add(ruleInclude, position73)
+			}
+			return true
+		l72:
+			position, tokenIndex = position72, tokenIndex72
+			return false
+		},
+		/* 14 Refer <- <('@' 'r' 'e' 'f' '(' String ')')> */
+		func() bool {
+			position74, tokenIndex74 := position, tokenIndex
+			{
+				position75 := position
+				if buffer[position] != rune('@') {
+					goto l74
+				}
+				position++
+				if buffer[position] != rune('r') {
+					goto l74
+				}
+				position++
+				if buffer[position] != rune('e') {
+					goto l74
+				}
+				position++
+				if buffer[position] != rune('f') {
+					goto l74
+				}
+				position++
+				if buffer[position] != rune('(') {
+					goto l74
+				}
+				position++
+				if !_rules[ruleString]() {
+					goto l74
+				}
+				if buffer[position] != rune(')') {
+					goto l74
+				}
+				position++
+				add(ruleRefer, position75)
+			}
+			return true
+		l74:
+			position, tokenIndex = position74, tokenIndex74
+			return false
+		},
+		/* 15 Func <- <('@' 'f' 'n' '(' String ')')> */
+		func() bool {
+			position76, tokenIndex76 := position, tokenIndex
+			{
+				position77 := position
+				if buffer[position] != rune('@') {
+					goto l76
+				}
+				position++
+				if buffer[position] != rune('f') {
+					goto l76
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l76
+				}
+				position++
+				if buffer[position] != rune('(') {
+					goto l76
+				}
+				position++
+				if !_rules[ruleString]() {
+					goto l76
+				}
+				if buffer[position] != rune(')') {
+					goto l76
+				}
+				position++
+				add(ruleFunc, position77)
+			}
+			return true
+		l76:
+			position, tokenIndex = position76, tokenIndex76
+			return false
+		},
+		/* 16 SimpleIdentifier <- <([0-9] / [A-Z] / [a-z] / '_' / '$')+> */
+		func() bool {
+			position78, tokenIndex78 := position, tokenIndex
+			{
+				position79 := position
+				{
+					position82, tokenIndex82 := position, tokenIndex
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l83
+					}
+					position++
+					goto l82
+				l83:
+					position, tokenIndex = position82, tokenIndex82
+					if c := buffer[position]; c < rune('A') || c > rune('Z') {
+						goto l84
+					}
+					position++
+					goto l82
+				l84:
+					position, tokenIndex = position82, tokenIndex82
+					if c := buffer[position]; c < rune('a') || c > rune('z') {
+						goto l85
+					}
+					position++
+					goto l82
+				l85:
+					position, tokenIndex = position82, tokenIndex82
+					if buffer[position] != rune('_') {
+						goto l86
+					}
+					position++
+					goto l82
+				l86:
+					position, tokenIndex = position82, tokenIndex82
+					if buffer[position] != rune('$') {
+						goto l78
+					}
+					position++
+				}
+			l82:
+			l80:
+				{
+					position81, tokenIndex81 := position, tokenIndex
+					{
+						position87, tokenIndex87 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l88
+						}
+						position++
+						goto l87
+					l88:
+						position, tokenIndex = position87, tokenIndex87
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l89
+						}
+						position++
+						goto l87
+					l89:
+						position, tokenIndex = position87, tokenIndex87
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l90
+						}
+						position++
+						goto l87
+					l90:
+						position, tokenIndex = position87, tokenIndex87
+						if buffer[position] != rune('_') {
+							goto l91
+						}
+						position++
+						goto l87
+					l91:
+						position, tokenIndex = position87, tokenIndex87
+						if buffer[position] != rune('$') {
+							goto l81
+						}
+						position++
+					}
+				l87:
+					goto l80
+				l81:
+					position, tokenIndex = position81, tokenIndex81
+				}
+				add(ruleSimpleIdentifier, position79)
+			}
+			return true
+		l78:
+			position, tokenIndex = position78, tokenIndex78
+			return false
+		},
+		/* 17 Identifier <- <(([0-9] / [A-Z] / [a-z] / '_' / '$')* (IdentifierEscape / IdentifierUnicode) ([0-9] / [A-Z] / [a-z] / '_' / '$' / IdentifierEscape / IdentifierUnicode)*)> */
+		func() bool {
+			position92, tokenIndex92 := position, tokenIndex
+			{
+				position93 := position
+			l94:
+				{
+					position95, tokenIndex95 := position, tokenIndex
+					{
+						position96, tokenIndex96 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l97
+						}
+						position++
+						goto l96
+					l97:
+						position, tokenIndex = position96, tokenIndex96
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l98
+						}
+						position++
+						goto l96
+					l98:
+						position, tokenIndex = position96, tokenIndex96
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l99
+						}
+						position++
+						goto l96
+					l99:
+						position, tokenIndex = position96, tokenIndex96
+						if buffer[position] != rune('_') {
+							goto l100
+						}
+						position++
+						goto l96
+					l100:
+						position, tokenIndex = position96, tokenIndex96
+						if buffer[position] != rune('$') {
+							goto l95
+						}
+						position++
+					}
+				l96:
+					goto l94
+				l95:
+					position, tokenIndex = position95, tokenIndex95
+				}
+				{
+					position101, tokenIndex101 := position, tokenIndex
+					if !_rules[ruleIdentifierEscape]() {
+						goto l102
+					}
+					goto l101
+				l102:
+					position, tokenIndex = position101, tokenIndex101
+					if !_rules[ruleIdentifierUnicode]() {
+						goto l92
+					}
+				}
+			l101:
+			l103:
+				{
+					position104, tokenIndex104 := position, tokenIndex
+					{
+						position105, tokenIndex105 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l106
+						}
+						position++
+						goto l105
+					l106:
+						position, tokenIndex = position105, tokenIndex105
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l107
+						}
+						position++
+						goto l105
+					l107:
+						position, tokenIndex = position105, tokenIndex105
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l108
+						}
+						position++
+						goto l105
+					l108:
+						position, tokenIndex = position105, tokenIndex105
+						if buffer[position] != rune('_') {
+							goto l109
+						}
+						position++
+						goto l105
+					l109:
+						position, tokenIndex = position105, tokenIndex105
+						if buffer[position] != rune('$') {
+							goto l110
+						}
+						position++
+						goto l105
+					l110:
+						position, tokenIndex = position105, tokenIndex105
+						if !_rules[ruleIdentifierEscape]() {
+							goto l111
+						}
+						goto l105
+					l111:
+						position, tokenIndex = position105, tokenIndex105
+						if !_rules[ruleIdentifierUnicode]() {
+							goto l104
+						}
+					}
+				l105:
+					goto l103
+				l104:
+					position, tokenIndex = position104, tokenIndex104
+				}
+				add(ruleIdentifier, position93)
+			}
+			return true
+		l92:
+			position, tokenIndex = position92, tokenIndex92
+			return false
+		},
+		/* 18 IdentifierEscape <- <('\\' UnicodeEscape)> */
+		func() bool {
+			position112, tokenIndex112 := position, tokenIndex
+			{
+				position113 := position
+				if buffer[position] != rune('\\') {
+					goto l112
+				}
+				position++
+				if !_rules[ruleUnicodeEscape]() {
+					goto l112
+				}
+				add(ruleIdentifierEscape, position113)
+			}
+			return true
+		l112:
+			position, tokenIndex = position112, tokenIndex112
+			return false
+		},
+		/* 19 IdentifierUnicode <- <(!ExtendedSpaceChar [\u0080-\U0010ffff])> */
+		func() bool {
+			position114, tokenIndex114 := position, tokenIndex
+			{
+				position115 := position
+				{
+					position116, tokenIndex116 := position, tokenIndex
+					if !_rules[ruleExtendedSpaceChar]() {
+						goto l116
+					}
+					goto l114
+				l116:
+					position, tokenIndex = position116, tokenIndex116
+				}
+				if c := buffer[position]; c < rune('\u0080') || c > rune('\U0010ffff') {
+					goto l114
+				}
+				position++
+				add(ruleIdentifierUnicode, position115)
+			}
+			return true
+		l114:
+			position, tokenIndex = position114, tokenIndex114
+			return false
+		},
+		/* 20 String <- <(StringPrefix? (SingleQuoteLiteral / DoubleQuoteLiteral))> */
+		func() bool {
+			position117, tokenIndex117 := position, tokenIndex
+			{
+				position118 := position
+				{
+					position119, tokenIndex119 := position, tokenIndex
+					if !_rules[ruleStringPrefix]() {
+						goto l119
+					}
+					goto l120
+				l119:
+					position, tokenIndex = position119, tokenIndex119
+				}
+			l120:
+				{
+					position121, tokenIndex121 := position, tokenIndex
+					if !_rules[ruleSingleQuoteLiteral]() {
+						goto l122
+					}
+					goto l121
+				l122:
+					position, tokenIndex = position121, tokenIndex121
+					if !_rules[ruleDoubleQuoteLiteral]() {
+						goto l117
+					}
+				}
+			l121:
+				add(ruleString, position118)
+			}
+			return true
+		l117:
+			position, tokenIndex = position117, tokenIndex117
+			return false
+		},
+		/* 21 StringPrefix <- <('b' / 'B' / 'u' / 'U')> */
+		func() bool {
+			position123, tokenIndex123 := position, tokenIndex
+			{
+				position124 := position
+				{
+					position125, tokenIndex125 := position, tokenIndex
+					if buffer[position] != rune('b') {
+						goto l126
+					}
+					position++
+					goto l125
+				l126:
+					position, tokenIndex = position125, tokenIndex125
+					if buffer[position] != rune('B') {
+						goto l127
+					}
+					position++
+					goto l125
+				l127:
+					position, tokenIndex = position125, tokenIndex125
+					if buffer[position] != rune('u') {
+						goto l128
+					}
+					position++
+					goto l125
+				l128:
+					position, tokenIndex = position125, tokenIndex125
+					if buffer[position] != rune('U') {
+						goto l123
+					}
+					position++
+				}
+			l125:
+				add(ruleStringPrefix, position124)
+			}
+			return true
+		l123:
+			position, tokenIndex = position123, tokenIndex123
+			return false
+		},
+		/* 22 SingleQuoteLiteral <- <('\'' (SingleQuoteEscape / ExtendedEscape / (!('\'' / '\\' / '\n' / '\r') .))* '\'')> */
+		func() bool {
+			position129, tokenIndex129 := position, tokenIndex
+			{
+				position130 := position
+				if buffer[position] != rune('\'') {
+					goto l129
+				}
+				position++
+			l131:
+				{
+					position132, tokenIndex132 := position, tokenIndex
+					{
+						position133, tokenIndex133 := position, tokenIndex
+						if !_rules[ruleSingleQuoteEscape]() {
+							goto l134
+						}
+						goto l133
+					l134:
+						position, tokenIndex = position133, tokenIndex133
+						if !_rules[ruleExtendedEscape]() {
+							goto l135
+						}
+						goto l133
+					l135:
+						position, tokenIndex = position133, tokenIndex133
+						{
+							position136, tokenIndex136 := position, tokenIndex
+							{
+								position137, tokenIndex137 := position, tokenIndex
+								if buffer[position] != rune('\'') {
+									goto l138
+								}
+								position++
+								goto l137
+							l138:
+								position, tokenIndex = position137, tokenIndex137
+								if buffer[position] != rune('\\') {
+									goto l139
+								}
+								position++
+								goto l137
+							l139:
+								position, tokenIndex = position137, tokenIndex137
+								if buffer[position] != rune('\n') {
+									goto l140
+								}
+								position++
+								goto l137
+							l140:
+								position, tokenIndex = position137, tokenIndex137
+								if buffer[position] != rune('\r') {
+									goto l136
+								}
+								position++
+							}
+						l137:
+							goto l132
+						l136:
+							position, tokenIndex = position136, tokenIndex136
+						}
+						if !matchDot() {
+							goto l132
+						}
+					}
+				l133:
+					goto l131
+				l132:
+					position, tokenIndex = position132, tokenIndex132
+				}
+				if buffer[position] != rune('\'') {
+					goto l129
+				}
+				position++
+				add(ruleSingleQuoteLiteral, position130)
+			}
+			return true
+		l129:
+			position, tokenIndex = position129, tokenIndex129
+			return false
+		},
+		/* 23 DoubleQuoteLiteral <- <('"' (DoubleQuoteEscape / ExtendedEscape / (!('"' / '\\' / '\n' / '\r') .))* '"')> */
+		func() bool {
+			position141, tokenIndex141 := position, tokenIndex
+			{
+				position142 := position
+				if buffer[position] != rune('"') {
+					goto l141
+				}
+				position++
+			l143:
+				{
+					position144, tokenIndex144 := position, tokenIndex
+					{
+						position145, tokenIndex145 := position, tokenIndex
+						if !_rules[ruleDoubleQuoteEscape]() {
+							goto l146
+						}
+						goto l145
+					l146:
+						position, tokenIndex = position145, tokenIndex145
+						if !_rules[ruleExtendedEscape]() {
+							goto l147
+						}
+						goto l145
+					l147:
+						position, tokenIndex = position145, tokenIndex145
+						{
+							position148, tokenIndex148 := position, tokenIndex
+							{
+								position149, tokenIndex149 := position, tokenIndex
+								if buffer[position] != rune('"') {
+									goto l150
+								}
+								position++
+								goto l149
+							l150:
+								position, tokenIndex = position149, tokenIndex149
+								if buffer[position] != rune('\\') {
+									goto l151
+								}
+								position++
+								goto l149
+							l151:
+								position, tokenIndex = position149, tokenIndex149
+								if buffer[position] != rune('\n') {
+									goto l152
+								}
+								position++
+								goto l149
+							l152:
+								position, tokenIndex = position149, tokenIndex149
+								if buffer[position] != rune('\r') {
+									goto l148
+								}
+								position++
+							}
+						l149:
+							goto l144
+						l148:
+							position, tokenIndex = position148, tokenIndex148
+						}
+						if !matchDot() {
+							goto l144
+						}
+					}
+				l145:
+					goto l143
+				l144:
+					position, tokenIndex = position144, tokenIndex144
+				}
+				if buffer[position] != rune('"') {
+					goto l141
+				}
+				position++
+				add(ruleDoubleQuoteLiteral, position142)
+			}
+			return true
+		l141:
+			position, tokenIndex = position141, tokenIndex141
+			return false
+		},
+		/* 24 SingleQuoteEscape <- <('\\' ('b' / 't' / 'n' / 'f' / 'r' / 'v' / '\'' / '"' / '\\' / '/' / UnicodeEscape / LongUnicodeEscape / HexEscape / OctalEscape))> */
+		func() bool {
+			position153, tokenIndex153 := position, tokenIndex
+			{
+				position154 := position
+				if buffer[position] != rune('\\') {
+					goto l153
+				}
+				position++
+				{
+					position155, tokenIndex155 := position, tokenIndex
+					if buffer[position] != rune('b') {
+						goto l156
+					}
+					position++
+					goto l155
+				l156:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('t') {
+						goto l157
+					}
+					position++
+					goto l155
+				l157:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('n') {
+						goto l158
+					}
+					position++
+					goto l155
+				l158:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('f') {
+						goto l159
+					}
+					position++
+					goto l155
+				l159:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('r') {
+						goto l160
+					}
+					position++
+					goto l155
+				l160:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('v') {
+						goto l161
+					}
+					position++
+					goto l155
+				l161:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('\'') {
+						goto l162
+					}
+					position++
+					goto l155
+				l162:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('"') {
+						goto l163
+					}
+					position++
+					goto l155
+				l163:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('\\') {
+						goto l164
+					}
+					position++
+					goto l155
+				l164:
+					position, tokenIndex = position155, tokenIndex155
+					if buffer[position] != rune('/') {
+						goto l165
+					}
+					position++
+					goto l155
+				l165:
+					position, tokenIndex = position155, tokenIndex155
+					if !_rules[ruleUnicodeEscape]() {
+						goto l166
+					}
+					goto l155
+				l166:
+					position, tokenIndex = position155, tokenIndex155
+					if !_rules[ruleLongUnicodeEscape]() {
+						goto l167
+					}
+					goto l155
+				l167:
+					position, tokenIndex = position155, tokenIndex155
+					if !_rules[ruleHexEscape]() {
+						goto l168
+					}
+					goto l155
+				l168:
+					position, tokenIndex = position155, tokenIndex155
+					if !_rules[ruleOctalEscape]() {
+						goto l153
+					}
+				}
+			l155:
+				add(ruleSingleQuoteEscape, position154)
+			}
+			return true
+		l153:
+			position, tokenIndex = position153, tokenIndex153
+			return false
+		},
+		/* 25 DoubleQuoteEscape <- <('\\' ('b' / 't' / 'n' / 'f' / 'r' / '"' / '\\' / '/' / UnicodeEscape))> */
+		func() bool {
+			position169, tokenIndex169 := position, tokenIndex
+			{
+				position170 := position
+				if buffer[position] != rune('\\') {
+					goto l169
+				}
+				position++
+				{
+					position171, tokenIndex171 := position, tokenIndex
+					if buffer[position] != rune('b') {
+						goto l172
+					}
+					position++
+					goto l171
+				l172:
+					position, tokenIndex = position171, tokenIndex171
+					if buffer[position] != rune('t') {
+						goto l173
+					}
+					position++
+					goto l171
+				l173:
+					position, tokenIndex = position171, tokenIndex171
+					if buffer[position] != rune('n') {
+						goto l174
+					}
+					position++
+					goto l171
+				l174:
+					position, tokenIndex = position171, tokenIndex171
+					if buffer[position] != rune('f') {
+						goto l175
+					}
+					position++
+					goto l171
+				l175:
+					position, tokenIndex = position171, tokenIndex171
+					if buffer[position] != rune('r') {
+						goto l176
+					}
+					position++
+					goto l171
+				l176:
+					position, tokenIndex = position171, tokenIndex171
+					if buffer[position] != rune('"') {
+						goto l177
+					}
+					position++
+					goto l171
+				l177:
+					position, tokenIndex = position171, tokenIndex171
+					if buffer[position] != rune('\\') {
+						goto l178
+					}
+					position++
+					goto l171
+				l178:
+					position, tokenIndex = position171, tokenIndex171
+					if buffer[position] != rune('/') {
+						goto l179
+					}
+					position++
+					goto l171
+				l179:
+					position, tokenIndex = position171, tokenIndex171
+					if !_rules[ruleUnicodeEscape]() {
+						goto l169
+					}
+				}
+			l171:
+				add(ruleDoubleQuoteEscape, position170)
+			}
+			return true
+		l169:
+			position, tokenIndex = position169, tokenIndex169
+			return false
+		},
+		/* 26 ExtendedEscape <- <('\\' (LineContinuation / (!'u' .)))> */
+		func() bool {
+			position180, tokenIndex180 := position, tokenIndex
+			{
+				position181 := position
+				if buffer[position] != rune('\\') {
+					goto l180
+				}
+				position++
+				{
+					position182, tokenIndex182 := position, tokenIndex
+					if !_rules[ruleLineContinuation]() {
+						goto l183
+					}
+					goto l182
+				l183:
+					position, tokenIndex = position182, tokenIndex182
+					{
+						position184, tokenIndex184 := position, tokenIndex
+						if buffer[position] != rune('u') {
+							goto l184
+						}
+						position++
+						goto l180
+					l184:
+						position, tokenIndex = position184, tokenIndex184
+					}
+					if !matchDot() {
+						goto l180
+					}
+				}
+			l182:
+				add(ruleExtendedEscape, position181)
+			}
+			return true
+		l180:
+			position, tokenIndex = position180, tokenIndex180
+			return false
+		},
+		/* 27 LineContinuation <- <(('\r' '\n') / '\n' / '\r' / '\u2028' / '\u2029')> */
+		func() bool {
+			position185, tokenIndex185 := position, tokenIndex
+			{
+				position186 := position
+				{
+					position187, tokenIndex187 := position, tokenIndex
+					if buffer[position] != rune('\r') {
+						goto l188
+					}
+					position++
+					if buffer[position] != rune('\n') {
+						goto l188
+					}
+					position++
+					goto l187
+				l188:
+					position, tokenIndex = position187, tokenIndex187
+					if buffer[position] != rune('\n') {
+						goto l189
+					}
+					position++
+					goto l187
+				l189:
+					position, tokenIndex = position187, tokenIndex187
+					if buffer[position] != rune('\r') {
+						goto l190
+					}
+					position++
+					goto l187
+				l190:
+					position, tokenIndex = position187, tokenIndex187
+					if buffer[position] != rune('\u2028') {
+						goto l191
+					}
+					position++
+					goto l187
+				l191:
+					position, tokenIndex = position187, tokenIndex187
+					if buffer[position] != rune('\u2029') {
+						goto l185
+					}
+					position++
+				}
+			l187:
+				add(ruleLineContinuation, position186)
+			}
+			return true
+		l185:
+			position, tokenIndex = position185, tokenIndex185
+			return false
+		},
+		/* 28 UnicodeEscape <- <('u' HexDigit HexDigit HexDigit HexDigit)> */
+		func() bool {
+			position192, tokenIndex192 := position, tokenIndex
+			{
+				position193 := position
+				if buffer[position] != rune('u') {
+					goto l192
+				}
+				position++
+				if !_rules[ruleHexDigit]() {
+					goto l192
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l192
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l192
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l192
+				}
+				add(ruleUnicodeEscape, position193)
+			}
+			return true
+		l192:
+			position, tokenIndex = position192, tokenIndex192
+			return false
+		},
+		/* 29 LongUnicodeEscape <- <('U' HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit HexDigit)> */
+		func() bool {
+			position194, tokenIndex194 := position, tokenIndex
+			{
+				position195 := position
+				if buffer[position] != rune('U') {
+					goto l194
+				}
+				position++
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l194
+				}
+				add(ruleLongUnicodeEscape, position195)
+			}
+			return true
+		l194:
+			position, tokenIndex = position194, tokenIndex194
+			return false
+		},
+		/* 30 HexEscape <- <('x' HexDigit HexDigit)> */
+		func() bool {
+			position196, tokenIndex196 := position, tokenIndex
+			{
+				position197 := position
+				if buffer[position] != rune('x') {
+					goto l196
+				}
+				position++
+				if !_rules[ruleHexDigit]() {
+					goto l196
+				}
+				if !_rules[ruleHexDigit]() {
+					goto l196
+				}
+				add(ruleHexEscape, position197)
+			}
+			return true
+		l196:
+			position, tokenIndex = position196, tokenIndex196
+			return false
+		},
+		/* 31 OctalEscape <- <([0-7] [0-7]? [0-7]?)> */
+		func() bool {
+			position198, tokenIndex198 := position, tokenIndex
+			{
+				position199 := position
+				if c := buffer[position]; c < rune('0') || c > rune('7') {
+					goto l198
+				}
+				position++
+				{
+					position200, tokenIndex200 := position, tokenIndex
+					if c := buffer[position]; c < rune('0') || c > rune('7') {
+						goto l200
+					}
+					position++
+					goto l201
+				l200:
+					position, tokenIndex = position200, tokenIndex200
+				}
+			l201:
+				{
+					position202, tokenIndex202 := position, tokenIndex
+					if c := buffer[position]; c < rune('0') || c > rune('7') {
+						goto l202
+					}
+					position++
+					goto l203
+				l202:
+					position, tokenIndex = position202, tokenIndex202
+				}
+			l203:
+				add(ruleOctalEscape, position199)
+			}
+			return true
+		l198:
+			position, tokenIndex = position198, tokenIndex198
+			return false
+		},
+		/* 32 HexDigit <- <([a-f] / [A-F] / [0-9])> */
+		func() bool {
+			position204, tokenIndex204 := position, tokenIndex
+			{
+				position205 := position
+				{
+					position206, tokenIndex206 := position, tokenIndex
+					if c := buffer[position]; c < rune('a') || c > rune('f') {
+						goto l207
+					}
+					position++
+					goto l206
+				l207:
+					position, tokenIndex = position206, tokenIndex206
+					if c := buffer[position]; c < rune('A') || c > rune('F') {
+						goto l208
+					}
+					position++
+					goto l206
+				l208:
+					position, tokenIndex = position206, tokenIndex206
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l204
+					}
+					position++
+				}
+			l206:
+				add(ruleHexDigit, position205)
+			}
+			return true
+		l204:
+			position, tokenIndex = position204, tokenIndex204
+			return false
+		},
+		/* 33 MultilineString <- <('\'' '\'' '\'' (!('\'' '\'' '\'') .)* ('\'' '\'' '\''))> */
+		func() bool {
+			position209, tokenIndex209 := position, tokenIndex
+			{
+				position210 := position
+				if buffer[position] != rune('\'') {
+					goto l209
+				}
+				position++
+				if buffer[position] != rune('\'') {
+					goto l209
+				}
+				position++
+				if buffer[position] != rune('\'') {
+					goto l209
+				}
+				position++
+			l211:
+				{
+					position212, tokenIndex212 := position, tokenIndex
+					{
+						position213, tokenIndex213 := position, tokenIndex
+						if buffer[position] != rune('\'') {
+							goto l213
+						}
+						position++
+						if buffer[position] != rune('\'') {
+							goto l213
+						}
+						position++
+						if buffer[position] != rune('\'') {
+							goto l213
+						}
+						position++
+						goto l212
+					l213:
+						position, tokenIndex = position213, tokenIndex213
+					}
+					if !matchDot() {
+						goto l212
+					}
+					goto l211
+				l212:
+					position, tokenIndex = position212, tokenIndex212
+				}
+				if buffer[position] != rune('\'') {
+					goto l209
+				}
+				position++
+				if buffer[position] != rune('\'') {
+					goto l209
+				}
+				position++
+				if buffer[position] != rune('\'') {
+					goto l209
+				}
+				position++
+				add(ruleMultilineString, position210)
+			}
+			return true
+		l209:
+			position, tokenIndex = position209, tokenIndex209
+			return false
+		},
+		/* 34 Quoteless <- <(!((True / False / Null / Number) QuotelessEnd) !('{' / '}' / '[' / ']' / '(' / ')' / ',' / ':' / '"' / '\'' / ' ' / '\t' / '\r' / '\n') (!('\r' / '\n') .)+)> */
+		func() bool {
+			position214, tokenIndex214 := position, tokenIndex
+			{
+				position215 := position
+				{
+					position216, tokenIndex216 := position, tokenIndex
+					{
+						position217, tokenIndex217 := position, tokenIndex
+						if !_rules[ruleTrue]() {
+							goto l218
+						}
+						goto l217
+					l218:
+						position, tokenIndex = position217, tokenIndex217
+						if !_rules[ruleFalse]() {
+							goto l219
+						}
+						goto l217
+					l219:
+						position, tokenIndex = position217, tokenIndex217
+						if !_rules[ruleNull]() {
+							goto l220
+						}
+						goto l217
+					l220:
+						position, tokenIndex = position217, tokenIndex217
+						if !_rules[ruleNumber]() {
+							goto l216
+						}
+					}
+				l217:
+					if !_rules[ruleQuotelessEnd]() {
+						goto l216
+					}
+					goto l214
+				l216:
+					position, tokenIndex = position216, tokenIndex216
+				}
+				{
+					position221, tokenIndex221 := position, tokenIndex
+					{
+						position222, tokenIndex222 := position, tokenIndex
+						if buffer[position] != rune('{') {
+							goto l223
+						}
+						position++
+						goto l222
+					l223:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('}') {
+							goto l224
+						}
+						position++
+						goto l222
+					l224:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('[') {
+							goto l225
+						}
+						position++
+						goto l222
+					l225:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune(']') {
+							goto l226
+						}
+						position++
+						goto l222
+					l226:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('(') {
+							goto l227
+						}
+						position++
+						goto l222
+					l227:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune(')') {
+							goto l228
+						}
+						position++
+						goto l222
+					l228:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune(',') {
+							goto l229
+						}
+						position++
+						goto l222
+					l229:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune(':') {
+							goto l230
+						}
+						position++
+						goto l222
+					l230:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('"') {
+							goto l231
+						}
+						position++
+						goto l222
+					l231:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('\'') {
+							goto l232
+						}
+						position++
+						goto l222
+					l232:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune(' ') {
+							goto l233
+						}
+						position++
+						goto l222
+					l233:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('\t') {
+							goto l234
+						}
+						position++
+						goto l222
+					l234:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('\r') {
+							goto l235
+						}
+						position++
+						goto l222
+					l235:
+						position, tokenIndex = position222, tokenIndex222
+						if buffer[position] != rune('\n') {
+							goto l221
+						}
+						position++
+					}
+				l222:
+					goto l214
+				l221:
+					position, tokenIndex = position221, tokenIndex221
+				}
+				{
+					position238, tokenIndex238 := position, tokenIndex
+					{
+						position239, tokenIndex239 := position, tokenIndex
+						if buffer[position] != rune('\r') {
+							goto l240
+						}
+						position++
+						goto l239
+					l240:
+						position, tokenIndex = position239, tokenIndex239
+						if buffer[position] != rune('\n') {
+							goto l238
+						}
+						position++
+					}
+				l239:
+					goto l214
+				l238:
+					position, tokenIndex = position238, tokenIndex238
+				}
+				if !matchDot() {
+					goto l214
+				}
+			l236:
+				{
+					position237, tokenIndex237 := position, tokenIndex
+					{
+						position241, tokenIndex241 := position, tokenIndex
+						{
+							position242, tokenIndex242 := position, tokenIndex
+							if buffer[position] != rune('\r') {
+								goto l243
+							}
+							position++
+							goto l242
+						l243:
+							position, tokenIndex = position242, tokenIndex242
+							if buffer[position] != rune('\n') {
+								goto l241
+							}
+							position++
+						}
+					l242:
+						goto l237
+					l241:
+						position, tokenIndex = position241, tokenIndex241
+					}
+					if !matchDot() {
+						goto l237
+					}
+					goto l236
+				l237:
+					position, tokenIndex = position237, tokenIndex237
+				}
+				add(ruleQuoteless, position215)
+			}
+			return true
+		l214:
+			position, tokenIndex = position214, tokenIndex214
+			return false
+		},
+		/* 35 QuotelessEnd <- <((' ' / '\t')* ('\r' / '\n' / ',' / ']' / ')' / '}' / '#' / ('/' '/') / ('/' '*') / EOT))> */
+		func() bool {
+			position244, tokenIndex244 := position, tokenIndex
+			{
+				position245 := position
+			l246:
+				{
+					position247, tokenIndex247 := position, tokenIndex
+					{
+						position248, tokenIndex248 := position, tokenIndex
+						if buffer[position] != rune(' ') {
+							goto l249
+						}
+						position++
+						goto l248
+					l249:
+						position, tokenIndex = position248, tokenIndex248
+						if buffer[position] != rune('\t') {
+							goto l247
+						}
+						position++
+					}
+				l248:
+					goto l246
+				l247:
+					position, tokenIndex = position247, tokenIndex247
+				}
+				{
+					position250, tokenIndex250 := position, tokenIndex
+					if buffer[position] != rune('\r') {
+						goto l251
+					}
+					position++
+					goto l250
+				l251:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune('\n') {
+						goto l252
+					}
+					position++
+					goto l250
+				l252:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune(',') {
+						goto l253
+					}
+					position++
+					goto l250
+				l253:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune(']') {
+						goto l254
+					}
+					position++
+					goto l250
+				l254:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune(')') {
+						goto l255
+					}
+					position++
+					goto l250
+				l255:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune('}') {
+						goto l256
+					}
+					position++
+					goto l250
+				l256:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune('#') {
+						goto l257
+					}
+					position++
+					goto l250
+				l257:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune('/') {
+						goto l258
+					}
+					position++
+					if buffer[position] != rune('/') {
+						goto l258
+					}
+					position++
+					goto l250
+				l258:
+					position, tokenIndex = position250, tokenIndex250
+					if buffer[position] != rune('/') {
+						goto l259
+					}
+					position++
+					if buffer[position] != rune('*') {
+						goto l259
+					}
+					position++
+					goto l250
+				l259:
+					position, tokenIndex = position250, tokenIndex250
+					if !_rules[ruleEOT]() {
+						goto l244
+					}
+				}
+			l250:
+				add(ruleQuotelessEnd, position245)
+			}
+			return true
+		l244:
+			position, tokenIndex = position244, tokenIndex244
+			return false
+		},
+		/* 36 QuotelessKey <- <(!('{' / '}' / '[' / ']' / ',' / ':' / '"' / '\'' / ' ' / '\t' / '\r' / '\n') .)+> */
+		func() bool {
+			position260, tokenIndex260 := position, tokenIndex
+			{
+				position261 := position
+				{
+					position264, tokenIndex264 := position, tokenIndex
+					{
+						position265, tokenIndex265 := position, tokenIndex
+						if buffer[position] != rune('{') {
+							goto l266
+						}
+						position++
+						goto l265
+					l266:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('}') {
+							goto l267
+						}
+						position++
+						goto l265
+					l267:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('[') {
+							goto l268
+						}
+						position++
+						goto l265
+					l268:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune(']') {
+							goto l269
+						}
+						position++
+						goto l265
+					l269:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune(',') {
+							goto l270
+						}
+						position++
+						goto l265
+					l270:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune(':') {
+							goto l271
+						}
+						position++
+						goto l265
+					l271:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('"') {
+							goto l272
+						}
+						position++
+						goto l265
+					l272:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('\'') {
+							goto l273
+						}
+						position++
+						goto l265
+					l273:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune(' ') {
+							goto l274
+						}
+						position++
+						goto l265
+					l274:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('\t') {
+							goto l275
+						}
+						position++
+						goto l265
+					l275:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('\r') {
+							goto l276
+						}
+						position++
+						goto l265
+					l276:
+						position, tokenIndex = position265, tokenIndex265
+						if buffer[position] != rune('\n') {
+							goto l264
+						}
+						position++
+					}
+				l265:
+					goto l260
+				l264:
+					position, tokenIndex = position264, tokenIndex264
+				}
+				if !matchDot() {
+					goto l260
+				}
+			l262:
+				{
+					position263, tokenIndex263 := position, tokenIndex
+					{
+						position277, tokenIndex277 := position, tokenIndex
+						{
+							position278, tokenIndex278 := position, tokenIndex
+							if buffer[position] != rune('{') {
+								goto l279
+							}
+							position++
+							goto l278
+						l279:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune('}') {
+								goto l280
+							}
+							position++
+							goto l278
+						l280:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune('[') {
+								goto l281
+							}
+							position++
+							goto l278
+						l281:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune(']') {
+								goto l282
+							}
+							position++
+							goto l278
+						l282:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune(',') {
+								goto l283
+							}
+							position++
+							goto l278
+						l283:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune(':') {
+								goto l284
+							}
+							position++
+							goto l278
+						l284:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune('"') {
+								goto l285
+							}
+							position++
+							goto l278
+						l285:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune('\'') {
+								goto l286
+							}
+							position++
+							goto l278
+						l286:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune(' ') {
+								goto l287
+							}
+							position++
+							goto l278
+						l287:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune('\t') {
+								goto l288
+							}
+							position++
+							goto l278
+						l288:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune('\r') {
+								goto l289
+							}
+							position++
+							goto l278
+						l289:
+							position, tokenIndex = position278, tokenIndex278
+							if buffer[position] != rune('\n') {
+								goto l277
+							}
+							position++
+						}
+					l278:
+						goto l263
+					l277:
+						position, tokenIndex = position277, tokenIndex277
+					}
+					if !matchDot() {
+						goto l263
+					}
+					goto l262
+				l263:
+					position, tokenIndex = position263, tokenIndex263
+				}
+				add(ruleQuotelessKey, position261)
+			}
+			return true
+		l260:
+			position, tokenIndex = position260, tokenIndex260
+			return false
+		},
+		/* 37 True <- <(('t' 'r' 'u' 'e') / ('T' 'r' 'u' 'e'))> */
+		func() bool {
+			position290, tokenIndex290 := position, tokenIndex
+			{
+				position291 := position
+				{
+					position292, tokenIndex292 := position, tokenIndex
+					if buffer[position] != rune('t') {
+						goto l293
+					}
+					position++
+					if buffer[position] != rune('r') {
+						goto l293
+					}
+					position++
+					if buffer[position] != rune('u') {
+						goto l293
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l293
+					}
+					position++
+					goto l292
+				l293:
+					position, tokenIndex = position292, tokenIndex292
+					if buffer[position] != rune('T') {
+						goto l290
+					}
+					position++
+					if buffer[position] != rune('r') {
+						goto l290
+					}
+					position++
+					if buffer[position] != rune('u') {
+						goto l290
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l290
+					}
+					position++
+				}
+			l292:
+				add(ruleTrue, position291)
+			}
+			return true
+		l290:
+			position, tokenIndex = position290, tokenIndex290
+			return false
+		},
+		/* 38 False <- <(('f' 'a' 'l' 's' 'e') / ('F' 'a' 'l' 's' 'e'))> */
+		func() bool {
+			position294, tokenIndex294 := position, tokenIndex
+			{
+				position295 := position
+				{
+					position296, tokenIndex296 := position, tokenIndex
+					if buffer[position] != rune('f') {
+						goto l297
+					}
+					position++
+					if buffer[position] != rune('a') {
+						goto l297
+					}
+					position++
+					if buffer[position] != rune('l') {
+						goto l297
+					}
+					position++
+					if buffer[position] != rune('s') {
+						goto l297
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l297
+					}
+					position++
+					goto l296
+				l297:
+					position, tokenIndex = position296, tokenIndex296
+					if buffer[position] != rune('F') {
+						goto l294
+					}
+					position++
+					if buffer[position] != rune('a') {
+						goto l294
+					}
+					position++
+					if buffer[position] != rune('l') {
+						goto l294
+					}
+					position++
+					if buffer[position] != rune('s') {
+						goto l294
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l294
+					}
+					position++
+				}
+			l296:
+				add(ruleFalse, position295)
+			}
+			return true
+		l294:
+			position, tokenIndex = position294, tokenIndex294
+			return false
+		},
+		/* 39 Null <- <(('n' 'u' 'l' 'l') / ('N' 'o' 'n' 'e'))> */
+		func() bool {
+			position298, tokenIndex298 := position, tokenIndex
+			{
+				position299 := position
+				{
+					position300, tokenIndex300 := position, tokenIndex
+					if buffer[position] != rune('n') {
+						goto l301
+					}
+					position++
+					if buffer[position] != rune('u') {
+						goto l301
+					}
+					position++
+					if buffer[position] != rune('l') {
+						goto l301
+					}
+					position++
+					if buffer[position] != rune('l') {
+						goto l301
+					}
+					position++
+					goto l300
+				l301:
+					position, tokenIndex = position300, tokenIndex300
+					if buffer[position] != rune('N') {
+						goto l298
+					}
+					position++
+					if buffer[position] != rune('o') {
+						goto l298
+					}
+					position++
+					if buffer[position] != rune('n') {
+						goto l298
+					}
+					position++
+					if buffer[position] != rune('e') {
+						goto l298
+					}
+					position++
+				}
+			l300:
+				add(ruleNull, position299)
+			}
+			return true
+		l298:
+			position, tokenIndex = position298, tokenIndex298
+			return false
+		},
+		/* 40 Number <- <((Minus / Plus)? (HexNumber / Infinity / NaN / PyInfinity / PyNaN / (IntegralPart FractionalPart? ExponentPart?) / (LeadingDecimal ExponentPart?)))> */
+		func() bool {
+			position302, tokenIndex302 := position, tokenIndex
+			{
+				position303 := position
+				{
+					position304, tokenIndex304 := position, tokenIndex
+					{
+						position306, tokenIndex306 := position, tokenIndex
+						if !_rules[ruleMinus]() {
+							goto l307
+						}
+						goto l306
+					l307:
+						position, tokenIndex = position306, tokenIndex306
+						if !_rules[rulePlus]() {
+							goto l304
+						}
+					}
+				l306:
+					goto l305
+				l304:
+					position, tokenIndex = position304, tokenIndex304
+				}
+			l305:
+				{
+					position308, tokenIndex308 := position, tokenIndex
+					if !_rules[ruleHexNumber]() {
+						goto l309
+					}
+					goto l308
+				l309:
+					position, tokenIndex = position308, tokenIndex308
+					if !_rules[ruleInfinity]() {
+						goto l310
+					}
+					goto l308
+				l310:
+					position, tokenIndex = position308, tokenIndex308
+					if !_rules[ruleNaN]() {
+						goto l311
+					}
+					goto l308
+				l311:
+					position, tokenIndex = position308, tokenIndex308
+					if !_rules[rulePyInfinity]() {
+						goto l312
+					}
+					goto l308
+				l312:
+					position, tokenIndex = position308, tokenIndex308
+					if !_rules[rulePyNaN]() {
+						goto l313
+					}
+					goto l308
+				l313:
+					position, tokenIndex = position308, tokenIndex308
+					if !_rules[ruleIntegralPart]() {
+						goto l314
+					}
+					{
+						position315, tokenIndex315 := position, tokenIndex
+						if !_rules[ruleFractionalPart]() {
+							goto l315
+						}
+						goto l316
+					l315:
+						position, tokenIndex = position315, tokenIndex315
+					}
+				l316:
+					{
+						position317, tokenIndex317 := position, tokenIndex
+						if !_rules[ruleExponentPart]() {
+							goto l317
+						}
+						goto l318
+					l317:
+						position, tokenIndex = position317, tokenIndex317
+					}
+				l318:
+					goto l308
+				l314:
+					position, tokenIndex = position308, tokenIndex308
+					if !_rules[ruleLeadingDecimal]() {
+						goto l302
+					}
+					{
+						position319, tokenIndex319 := position, tokenIndex
+						if !_rules[ruleExponentPart]() {
+							goto l319
+						}
+						goto l320
+					l319:
+						position, tokenIndex = position319, tokenIndex319
+					}
+				l320:
+				}
+			l308:
+				add(ruleNumber, position303)
+			}
+			return true
+		l302:
+			position, tokenIndex = position302, tokenIndex302
+			return false
+		},
+		/* 41 Minus <- <'-'> */
+		func() bool {
+			position321, tokenIndex321 := position, tokenIndex
+			{
+				position322 := position
+				if buffer[position] != rune('-') {
+					goto l321
+				}
+				position++
+				add(ruleMinus, position322)
+			}
+			return true
+		l321:
+			position, tokenIndex = position321, tokenIndex321
+			return false
+		},
+		/* 42 Plus <- <'+'> */
+		func() bool {
+			position323, tokenIndex323 := position, tokenIndex
+			{
+				position324 := position
+				if buffer[position] != rune('+') {
+					goto l323
+				}
+				position++
+				add(rulePlus, position324)
+			}
+			return true
+		l323:
+			position, tokenIndex = position323, tokenIndex323
+			return false
+		},
+		/* 43 IntegralPart <- <('0' / ([1-9] [0-9]*))> */
+		func() bool {
+			position325, tokenIndex325 := position, tokenIndex
+			{
+				position326 := position
+				{
+					position327, tokenIndex327 := position, tokenIndex
+					if buffer[position] != rune('0') {
+						goto l328
+					}
+					position++
+					goto l327
+				l328:
+					position, tokenIndex = position327, tokenIndex327
+					if c := buffer[position]; c < rune('1') || c > rune('9') {
+						goto l325
+					}
+					position++
+				l329:
+					{
+						position330, tokenIndex330 := position, tokenIndex
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l330
+						}
+						position++
+						goto l329
+					l330:
+						position, tokenIndex = position330, tokenIndex330
+					}
+				}
+			l327:
+				add(ruleIntegralPart, position326)
+			}
+			return true
+		l325:
+			position, tokenIndex = position325, tokenIndex325
+			return false
+		},
+		/* 44 FractionalPart <- <('.' [0-9]*)> */
+		func() bool {
+			position331, tokenIndex331 := position, tokenIndex
+			{
+				position332 := position
+				if buffer[position] != rune('.') {
+					goto l331
+				}
+				position++
+			l333:
+				{
+					position334, tokenIndex334 := position, tokenIndex
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l334
+					}
+					position++
+					goto l333
+				l334:
+					position, tokenIndex = position334, tokenIndex334
+				}
+				add(ruleFractionalPart, position332)
+			}
+			return true
+		l331:
+			position, tokenIndex = position331, tokenIndex331
+			return false
+		},
+		/* 45 LeadingDecimal <- <('.' [0-9]+)> */
+		func() bool {
+			position335, tokenIndex335 := position, tokenIndex
+			{
+				position336 := position
+				if buffer[position] != rune('.') {
+					goto l335
+				}
+				position++
+				if c := buffer[position]; c < rune('0') || c > rune('9') {
+					goto l335
+				}
+				position++
+			l337:
+				{
+					position338, tokenIndex338 := position, tokenIndex
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l338
+					}
+					position++
+					goto l337
+				l338:
+					position, tokenIndex = position338, tokenIndex338
+				}
+				add(ruleLeadingDecimal, position336)
+			}
+			return true
+		l335:
+			position, tokenIndex = position335, tokenIndex335
+			return false
+		},
+		/* 46 ExponentPart <- <(('e' / 'E') ('+' / '-')? [0-9]+)> */
+		func() bool {
+			position339, tokenIndex339 := position, tokenIndex
+			{
+				position340 := position
+				{
+					position341, tokenIndex341 := position, tokenIndex
+					if buffer[position] != rune('e') {
+						goto l342
+					}
+					position++
+					goto l341
+				l342:
+					position, tokenIndex = position341, tokenIndex341
+					if buffer[position] != rune('E') {
+						goto l339
+					}
+					position++
+				}
+			l341:
+				{
+					position343, tokenIndex343 := position, tokenIndex
+					{
+						position345, tokenIndex345 := position, tokenIndex
+						if buffer[position] != rune('+') {
+							goto l346
+						}
+						position++
+						goto l345
+					l346:
+						position, tokenIndex = position345, tokenIndex345
+						if buffer[position] != rune('-') {
+							goto l343
+						}
+						position++
+					}
+				l345:
+					goto l344
+				l343:
+					position, tokenIndex = position343, tokenIndex343
+				}
+			l344:
+				if c := buffer[position]; c < rune('0') || c > rune('9') {
+					goto l339
+				}
+				position++
+			l347:
+				{
+					position348, tokenIndex348 := position, tokenIndex
+					if c := buffer[position]; c < rune('0') || c > rune('9') {
+						goto l348
+					}
+					position++
+					goto l347
+				l348:
+					position, tokenIndex = position348, tokenIndex348
+				}
+				add(ruleExponentPart, position340)
+			}
+			return true
+		l339:
+			position, tokenIndex = position339, tokenIndex339
+			return false
+		},
+		/* 47 HexNumber <- <('0' ('x' / 'X') HexDigit+)> */
+		func() bool {
+			position349, tokenIndex349 := position, tokenIndex
+			{
+				position350 := position
+				if buffer[position] != rune('0') {
+					goto l349
+				}
+				position++
+				{
+					position351, tokenIndex351 := position, tokenIndex
+					if buffer[position] != rune('x') {
+						goto l352
+					}
+					position++
+					goto l351
+				l352:
+					position, tokenIndex = position351, tokenIndex351
+					if buffer[position] != rune('X') {
+						goto l349
+					}
+					position++
+				}
+			l351:
+				if !_rules[ruleHexDigit]() {
+					goto l349
+				}
+			l353:
+				{
+					position354, tokenIndex354 := position, tokenIndex
+					if !_rules[ruleHexDigit]() {
+						goto l354
+					}
+					goto l353
+				l354:
+					position, tokenIndex = position354, tokenIndex354
+				}
+				add(ruleHexNumber, position350)
+			}
+			return true
+		l349:
+			position, tokenIndex = position349, tokenIndex349
+			return false
+		},
+		/* 48 Infinity <- <('I' 'n' 'f' 'i' 'n' 'i' 't' 'y')> */
+		func() bool {
+			position355, tokenIndex355 := position, tokenIndex
+			{
+				position356 := position
+				if buffer[position] != rune('I') {
+					goto l355
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l355
+				}
+				position++
+				if buffer[position] != rune('f') {
+					goto l355
+				}
+				position++
+				if buffer[position] != rune('i') {
+					goto l355
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l355
+				}
+				position++
+				if buffer[position] != rune('i') {
+					goto l355
+				}
+				position++
+				if buffer[position] != rune('t') {
+					goto l355
+				}
+				position++
+				if buffer[position] != rune('y') {
+					goto l355
+				}
+				position++
+				add(ruleInfinity, position356)
+			}
+			return true
+		l355:
+			position, tokenIndex = position355, tokenIndex355
+			return false
+		},
+		/* 49 NaN <- <('N' 'a' 'N')> */
+		func() bool {
+			position357, tokenIndex357 := position, tokenIndex
+			{
+				position358 := position
+				if buffer[position] != rune('N') {
+					goto l357
+				}
+				position++
+				if buffer[position] != rune('a') {
+					goto l357
+				}
+				position++
+				if buffer[position] != rune('N') {
+					goto l357
+				}
+				position++
+				add(ruleNaN, position358)
+			}
+			return true
+		l357:
+			position, tokenIndex = position357, tokenIndex357
+			return false
+		},
+		/* 50 PyInfinity <- <('i' 'n' 'f')> */
+		func() bool {
+			position359, tokenIndex359 := position, tokenIndex
+			{
+				position360 := position
+				if buffer[position] != rune('i') {
+					goto l359
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l359
+				}
+				position++
+				if buffer[position] != rune('f') {
+					goto l359
+				}
+				position++
+				add(rulePyInfinity, position360)
+			}
+			return true
+		l359:
+			position, tokenIndex = position359, tokenIndex359
+			return false
+		},
+		/* 51 PyNaN <- <('n' 'a' 'n')> */
+		func() bool {
+			position361, tokenIndex361 := position, tokenIndex
+			{
+				position362 := position
+				if buffer[position] != rune('n') {
+					goto l361
+				}
+				position++
+				if buffer[position] != rune('a') {
+					goto l361
+				}
+				position++
+				if buffer[position] != rune('n') {
+					goto l361
+				}
+				position++
+				add(rulePyNaN, position362)
+			}
+			return true
+		l361:
+			position, tokenIndex = position361, tokenIndex361
+			return false
+		},
+		/* 52 Spacing <- <(Whitespace / ExtendedSpace / LongComment / LineComment / Pragma)*> */
+		func() bool {
+			{
+				position364 := position
+			l365:
+				{
+					position366, tokenIndex366 := position, tokenIndex
+					{
+						position367, tokenIndex367 := position, tokenIndex
+						if !_rules[ruleWhitespace]() {
+							goto l368
+						}
+						goto l367
+					l368:
+						position, tokenIndex = position367, tokenIndex367
+						if !_rules[ruleExtendedSpace]() {
+							goto l369
+						}
+						goto l367
+					l369:
+						position, tokenIndex = position367, tokenIndex367
+						if !_rules[ruleLongComment]() {
+							goto l370
+						}
+						goto l367
+					l370:
+						position, tokenIndex = position367, tokenIndex367
+						if !_rules[ruleLineComment]() {
+							goto l371
+						}
+						goto l367
+					l371:
+						position, tokenIndex = position367, tokenIndex367
+						if !_rules[rulePragma]() {
+							goto l366
+						}
+					}
+				l367:
+					goto l365
+				l366:
+					position, tokenIndex = position366, tokenIndex366
+				}
+				add(ruleSpacing, position364)
+			}
+			return true
+		},
+		/* 53 Whitespace <- <(' ' / '\t' / '\r' / '\n')+> */
+		func() bool {
+			position372, tokenIndex372 := position, tokenIndex
+			{
+				position373 := position
+				{
+					position376, tokenIndex376 := position, tokenIndex
+					if buffer[position] != rune(' ') {
+						goto l377
+					}
+					position++
+					goto l376
+				l377:
+					position, tokenIndex = position376, tokenIndex376
+					if buffer[position] != rune('\t') {
+						goto l378
+					}
+					position++
+					goto l376
+				l378:
+					position, tokenIndex = position376, tokenIndex376
+					if buffer[position] != rune('\r') {
+						goto l379
+					}
+					position++
+					goto l376
+				l379:
+					position, tokenIndex = position376, tokenIndex376
+					if buffer[position] != rune('\n') {
+						goto l372
+					}
+					position++
+				}
+			l376:
+			l374:
+				{
+					position375, tokenIndex375 := position, tokenIndex
+					{
+						position380, tokenIndex380 := position, tokenIndex
+						if buffer[position] != rune(' ') {
+							goto l381
+						}
+						position++
+						goto l380
+					l381:
+						position, tokenIndex = position380, tokenIndex380
+						if buffer[position] != rune('\t') {
+							goto l382
+						}
+						position++
+						goto l380
+					l382:
+						position, tokenIndex = position380, tokenIndex380
+						if buffer[position] != rune('\r') {
+							goto l383
+						}
+						position++
+						goto l380
+					l383:
+						position, tokenIndex = position380, tokenIndex380
+						if buffer[position] != rune('\n') {
+							goto l375
+						}
+						position++
+					}
+				l380:
+					goto l374
+				l375:
+					position, tokenIndex = position375, tokenIndex375
+				}
+				add(ruleWhitespace, position373)
+			}
+			return true
+		l372:
+			position, tokenIndex = position372, tokenIndex372
+			return false
+		},
+		/* 54 ExtendedSpace <- <ExtendedSpaceChar+> */
+		func() bool {
+			position384, tokenIndex384 := position, tokenIndex
+			{
+				position385 := position
+				if !_rules[ruleExtendedSpaceChar]() {
+					goto l384
+				}
+			l386:
+				{
+					position387, tokenIndex387 := position, tokenIndex
+					if !_rules[ruleExtendedSpaceChar]() {
+						goto l387
+					}
+					goto l386
+				l387:
+					position, tokenIndex = position387, tokenIndex387
+				}
+				add(ruleExtendedSpace, position385)
+			}
+			return true
+		l384:
+			position, tokenIndex = position384, tokenIndex384
+			return false
+		},
+		/* 55 LongComment <- <('/' '*' (!('*' '/') .)* ('*' '/'))> */
+		func() bool {
+			position388, tokenIndex388 := position, tokenIndex
+			{
+				position389 := position
+				if buffer[position] != rune('/') {
+					goto l388
+				}
+				position++
+				if buffer[position] != rune('*') {
+					goto l388
+				}
+				position++
+			l390:
+				{
+					position391, tokenIndex391 := position, tokenIndex
+					{
+						position392, tokenIndex392 := position, tokenIndex
+						if buffer[position] != rune('*') {
+							goto l392
+						}
+						position++
+						if buffer[position] != rune('/') {
+							goto l392
+						}
+						position++
+						goto l391
+					l392:
+						position, tokenIndex = position392, tokenIndex392
+					}
+					if !matchDot() {
+						goto l391
+					}
+					goto l390
+				l391:
+					position, tokenIndex = position391, tokenIndex391
+				}
+				if buffer[position] != rune('*') {
+					goto l388
+				}
+				position++
+				if buffer[position] != rune('/') {
+					goto l388
+				}
+				position++
+				add(ruleLongComment, position389)
+			}
+			return true
+		l388:
+			position, tokenIndex = position388, tokenIndex388
+			return false
+		},
+		/* 56 LineComment <- <('/' '/' (!('\r' / '\n') .)*)> */
+		func() bool {
+			position393, tokenIndex393 := position, tokenIndex
+			{
+				position394 := position
+				if buffer[position] != rune('/') {
+					goto l393
+				}
+				position++
+				if buffer[position] != rune('/') {
+					goto l393
+				}
+				position++
+			l395:
+				{
+					position396, tokenIndex396 := position, tokenIndex
+					{
+						position397, tokenIndex397 := position, tokenIndex
+						{
+							position398, tokenIndex398 := position, tokenIndex
+							if buffer[position] != rune('\r') {
+								goto l399
+							}
+							position++
+							goto l398
+						l399:
+							position, tokenIndex = position398, tokenIndex398
+							if buffer[position] != rune('\n') {
+								goto l397
+							}
+							position++
+						}
+					l398:
+						goto l396
+					l397:
+						position, tokenIndex = position397, tokenIndex397
+					}
+					if !matchDot() {
+						goto l396
+					}
+					goto l395
+				l396:
+					position, tokenIndex = position396, tokenIndex396
+				}
+				add(ruleLineComment, position394)
+			}
+			return true
+		l393:
+			position, tokenIndex = position393, tokenIndex393
+			return false
+		},
+		/* 57 Pragma <- <('#' (!('\r' / '\n') .)*)> */
+		func() bool {
+			position400, tokenIndex400 := position, tokenIndex
+			{
+				position401 := position
+				if buffer[position] != rune('#') {
+					goto l400
+				}
+				position++
+			l402:
+				{
+					position403, tokenIndex403 := position, tokenIndex
+					{
+						position404, tokenIndex404 := position, tokenIndex
+						{
+							position405, tokenIndex405 := position, tokenIndex
+							if buffer[position] != rune('\r') {
+								goto l406
+							}
+							position++
+							goto l405
+						l406:
+							position, tokenIndex = position405, tokenIndex405
+							if buffer[position] != rune('\n') {
+								goto l404
+							}
+							position++
+						}
+					l405:
+						goto l403
+					l404:
+						position, tokenIndex = position404, tokenIndex404
+					}
+					if !matchDot() {
+						goto l403
+					}
+					goto l402
+				l403:
+					position, tokenIndex = position403, tokenIndex403
+				}
+				add(rulePragma, position401)
+			}
+			return true
+		l400:
+			position, tokenIndex = position400, tokenIndex400
+			return false
+		},
+		/* 58 ExtendedSpaceChar <- <('\v' / '\f' / '\u00a0' / '\u1680' / [\u2000-\u200a] / '\u2028' / '\u2029' / '\u202f' / '\u205f' / '\u3000' / '\ufeff')> */
+		func() bool {
+			position407, tokenIndex407 := position, tokenIndex
+			{
+				position408 := position
+				{
+					position409, tokenIndex409 := position, tokenIndex
+					if buffer[position] != rune('\v') {
+						goto l410
+					}
+					position++
+					goto l409
+				l410:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\f') {
+						goto l411
+					}
+					position++
+					goto l409
+				l411:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\u00a0') {
+						goto l412
+					}
+					position++
+					goto l409
+				l412:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\u1680') {
+						goto l413
+					}
+					position++
+					goto l409
+				l413:
+					position, tokenIndex = position409, tokenIndex409
+					if c := buffer[position]; c < rune('\u2000') || c > rune('\u200a') {
+						goto l414
+					}
+					position++
+					goto l409
+				l414:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\u2028') {
+						goto l415
+					}
+					position++
+					goto l409
+				l415:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\u2029') {
+						goto l416
+					}
+					position++
+					goto l409
+				l416:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\u202f') {
+						goto l417
+					}
+					position++
+					goto l409
+				l417:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\u205f') {
+						goto l418
+					}
+					position++
+					goto l409
+				l418:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\u3000') {
+						goto l419
+					}
+					position++
+					goto l409
+				l419:
+					position, tokenIndex = position409, tokenIndex409
+					if buffer[position] != rune('\ufeff') {
+						goto l407
+					}
+					position++
+				}
+			l409:
+				add(ruleExtendedSpaceChar, position408)
+			}
+			return true
+		l407:
+			position, tokenIndex = position407, tokenIndex407
+			return false
+		},
+		/* 59 LWING <- <('{' Spacing)> */
+		func() bool {
+			position420, tokenIndex420 := position, tokenIndex
+			{
+				position421 := position
+				if buffer[position] != rune('{') {
+					goto l420
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l420
+				}
+				add(ruleLWING, position421)
+			}
+			return true
+		l420:
+			position, tokenIndex = position420, tokenIndex420
+			return false
+		},
+		/* 60 RWING <- <('}' Spacing)> */
+		func() bool {
+			position422, tokenIndex422 := position, tokenIndex
+			{
+				position423 := position
+				if buffer[position] != rune('}') {
+					goto l422
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l422
+				}
+				add(ruleRWING, position423)
+			}
+			return true
+		l422:
+			position, tokenIndex = position422, tokenIndex422
+			return false
+		},
+		/* 61 LBRK <- <('[' Spacing)> */
+		func() bool {
+			position424, tokenIndex424 := position, tokenIndex
+			{
+				position425 := position
+				if buffer[position] != rune('[') {
+					goto l424
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l424
+				}
+				add(ruleLBRK, position425)
+			}
+			return true
+		l424:
+			position, tokenIndex = position424, tokenIndex424
+			return false
+		},
+		/* 62 RBRK <- <(']' Spacing)> */
+		func() bool {
+			position426, tokenIndex426 := position, tokenIndex
+			{
+				position427 := position
+				if buffer[position] != rune(']') {
+					goto l426
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l426
+				}
+				add(ruleRBRK, position427)
+			}
+			return true
+		l426:
+			position, tokenIndex = position426, tokenIndex426
+			return false
+		},
+		/* 63 LPAR <- <('(' Spacing)> */
+		func() bool {
+			position428, tokenIndex428 := position, tokenIndex
+			{
+				position429 := position
+				if buffer[position] != rune('(') {
+					goto l428
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l428
+				}
+				add(ruleLPAR, position429)
+			}
+			return true
+		l428:
+			position, tokenIndex = position428, tokenIndex428
+			return false
+		},
+		/* 64 RPAR <- <(')' Spacing)> */
+		func() bool {
+			position430, tokenIndex430 := position, tokenIndex
+			{
+				position431 := position
+				if buffer[position] != rune(')') {
+					goto l430
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l430
+				}
+				add(ruleRPAR, position431)
+			}
+			return true
+		l430:
+			position, tokenIndex = position430, tokenIndex430
+			return false
+		},
+		/* 65 COMMA <- <(',' Spacing)> */
+		func() bool {
+			position432, tokenIndex432 := position, tokenIndex
+			{
+				position433 := position
+				if buffer[position] != rune(',') {
+					goto l432
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l432
+				}
+				add(ruleCOMMA, position433)
+			}
+			return true
+		l432:
+			position, tokenIndex = position432, tokenIndex432
+			return false
+		},
+		/* 66 COLON <- <(':' Spacing)> */
+		func() bool {
+			position434, tokenIndex434 := position, tokenIndex
+			{
+				position435 := position
+				if buffer[position] != rune(':') {
+					goto l434
+				}
+				position++
+				if !_rules[ruleSpacing]() {
+					goto l434
+				}
+				add(ruleCOLON, position435)
+			}
+			return true
+		l434:
+			position, tokenIndex = position434, tokenIndex434
+			return false
+		},
+		/* 67 EOT <- <!.> */
+		func() bool {
+			position436, tokenIndex436 := position, tokenIndex
+			{
+				position437 := position
+				{
+					position438, tokenIndex438 := position, tokenIndex
+					if !matchDot() {
+						goto l438
+					}
+					goto l436
+				l438:
+					position, tokenIndex = position438, tokenIndex438
+				}
+				add(ruleEOT, position437)
+			}
+			return true
+		l436:
+			position, tokenIndex = position436, tokenIndex436
+			return false
+		},
+	}
+	p.rules = _rules
+	return nil
+}
@@ -0,0 +1,257 @@
+package parser
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// NonFinite specifies how to convert non-finite numbers, i.e. Infinity
+// and NaN, which cannot be represented in strict JSON.
+type NonFinite int
+
+const (
+	NonFiniteNull   NonFinite = iota // convert to null
+	NonFiniteString                  // convert to string "Infinity", "-Infinity" or "NaN"
+	NonFiniteError                   // report an error
+)
+
+// numberFeatures records the extended features used by a number.
+func (p *parser) numberFeatures(t numberToken) {
+	if t.sign == '+' {
+		p.use(FeatureJSON5, t.begin-1)
+	}
+	switch t.kind {
+	case numHex, numInfinity, numNaN, numLeadingDecimal:
+		p.use(FeatureJSON5, t.begin)
+	case numPyInfinity, numPyNaN:
+		p.use(FeaturePython, t.begin)
+	case numDecimal:
+		if t.frac >= 0 && t.fracEnd() == t.frac+1 {
+			p.use(FeatureJSON5, t.frac)
+		}
+	}
+}
+
+// fracEnd returns the end offset of the fractional part.
+func (t numberToken) fracEnd() int {
+	if t.exp >= 0 {
+		return t.exp
+	}
+	return t.end
+}
+
+func (p *parser) appendNumber(t numberToken) error {
+	neg := t.sign == '-'
+	switch t.kind {
+	case numHex:
+		if neg {
+			p.buf = append(p.buf, '-')
+		}
+		digits := b2s(p.data[t.begin+2 : t.end])
+		if x, err := strconv.ParseUint(digits, 16, 64); err == nil {
+			p.buf = strconv.AppendUint(p.buf, x, 10)
+			break
+		}
+		x, _ := new(big.Int).SetString(digits, 16)
+		p.buf = x.Append(p.buf, 10)
+	case numInfinity, numNaN, numPyInfinity, numPyNaN:
+		return p.appendNonFinite(t, neg)
+	case numLeadingDecimal:
+		if neg {
+			p.buf = append(p.buf, '-')
+		}
+		p.buf = append(p.buf, '0')
+		p.buf = append(p.buf, p.data[t.begin:t.end]...)
+	default:
+		if neg {
+			p.buf = append(p.buf, '-')
+		}
+		if t.frac < 0 || t.fracEnd()-t.frac > 1 {
+			p.buf = append(p.buf, p.data[t.begin:t.end]...)
+			break
+		}
+		p.buf = append(p.buf, p.data[t.begin:t.frac]...)
+		p.buf = append(p.buf, p.data[t.fracEnd():t.end]...)
+	}
+	return nil
+}
+
+func (p *parser) appendNonFinite(t numberToken, neg bool) error {
+	var text string
+	switch t.kind {
+	case numInfinity, numPyInfinity:
+		text = "Infinity"
+		if neg {
+			text = "-Infinity"
+		}
+	case numNaN, numPyNaN:
+		text = "NaN"
+	}
+	switch p.opts.NonFinite {
+	case NonFiniteString:
+		p.buf = append(p.buf, '"')
+		p.buf = append(p.buf, text...)
+		p.buf = append(p.buf, '"')
+	case NonFiniteError:
+		return fmt.Errorf("non-finite number %s is not allowed at %v", text, p.position(t.begin))
+	default:
+		p.buf = append(p.buf, "null"...)
+	}
+	return nil
+}
+
+// unquoteExtended decodes the content of a string literal, which may
+// contain JSON5 escape sequences.
+func unquoteExtended(s []byte) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		c, size := utf8.DecodeRune(s[i:])
+		switch c {
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case 'v':
+			b.WriteByte('\v')
+		case '0':
+			if i+1 < len(s) && isDigit(s[i+1]) {
+				return "", fmt.Errorf("invalid escape sequence \\0%c", s[i+1])
+			}
+			b.WriteByte(0)
+		case 'x':
+			if i+2 >= len(s) || !isHexDigit(s[i+1]) || !isHexDigit(s[i+2]) {
+				return "", fmt.Errorf("invalid escape sequence \\x")
+			}
+			b.WriteRune(rune(hexValue(s[i+1 : i+3])))
+			i += 2
+		case 'u':
+			r := rune(hexValue(s[i+1 : i+5]))
+			i += 4
+			if utf16.IsSurrogate(r) && i+6 < len(s) && s[i+1] == '\\' && s[i+2] == 'u' {
+				if r2 := rune(hexValue(s[i+3 : i+7])); utf16.DecodeRune(r, r2) != unicode.ReplacementChar {
+					r = utf16.DecodeRune(r, r2)
+					i += 6
+				}
+			}
+			b.WriteRune(r)
+		case '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+		case '\n', '\u2028', '\u2029':
+			// line continuation
+		case '1', '2', '3', '4', '5', '6', '7', '8', '9':
+			return "", fmt.Errorf("invalid escape sequence \\%c", c)
+		default:
+			b.Write(s[i : i+size])
+		}
+		i += size - 1
+	}
+	return b.String(), nil
+}
+
+// unquoteIdentifier decodes an unquoted object key which may contain
+// unicode escape sequences, it reports an error if the key is not
+// a valid ECMAScript identifier.
+func unquoteIdentifier(s []byte) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c, size := utf8.DecodeRune(s[i:])
+		if c == '\\' {
+			c, size = rune(hexValue(s[i+2:i+6])), 6
+		}
+		if !isIdentifierRune(c, b.Len() == 0) {
+			return "", fmt.Errorf("invalid identifier %q", s)
+		}
+		b.WriteRune(c)
+		i += size
+	}
+	return b.String(), nil
+}
+
+func isIdentifierRune(c rune, first bool) bool {
+	switch {
+	case c == '$' || c == '_':
+		return true
+	case unicode.IsLetter(c) || unicode.Is(unicode.Nl, c):
+		return true
+	case first:
+		return false
+	case c == '\u200c' || c == '\u200d': // ZWNJ and ZWJ
+		return true
+	}
+	return unicode.In(c, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc)
+}
+
+// appendQuotedString appends s to buf as a JSON string.
+func appendQuotedString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 0x20 && c != '"' && c != '\\' {
+			continue
+		}
+		buf = append(buf, s[start:i]...)
+		switch c {
+		case '"', '\\':
+			buf = append(buf, '\\', c)
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		default:
+			buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+		}
+		start = i + 1
+	}
+	buf = append(buf, s[start:]...)
+	buf = append(buf, '"')
+	return buf
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isHexDigit(c byte) bool {
+	return isDigit(c) || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')
+}
+
+func hexValue(s []byte) (x int) {
+	for _, c := range s {
+		x <<= 4
+		switch {
+		case isDigit(c):
+			x |= int(c - '0')
+		case c >= 'a' && c <= 'f':
+			x |= int(c - 'a' + 10)
+		case c >= 'A' && c <= 'F':
+			x |= int(c - 'A' + 10)
+		}
+	}
+	return x
+}
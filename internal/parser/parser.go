@@ -2,397 +2,1046 @@ package parser
 
 import (
 	"bytes"
-	"crypto/rand"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"reflect"
-	"strconv"
 	"strings"
+	"unicode/utf8"
 	"unsafe"
-
-	"github.com/tidwall/gjson"
 )
 
-//go:generate peg json.peg
-
 const maxImportDepth = 10
 
-func Parse(data []byte, importRoot string, enableEnv bool, funcMap map[string]interface{}) ([]byte, error) {
-	return parse(data, importRoot, 0, enableEnv, funcMap)
+// Options holds the settings which control the parsing behavior.
+type Options struct {
+	Context     context.Context
+	FilePath    string
+	IncludeRoot string
+	EnableEnv   bool
+	FuncMap     map[string]interface{}
+
+	// Disallowed is a bit mask of extended features which are not
+	// allowed to be used in the input data.
+	Disallowed Feature
+
+	// JSON5 enables the JSON5 grammar, NonFinite specifies how to
+	// convert Infinity and NaN.
+	JSON5     bool
+	NonFinite NonFinite
+
+	// Hjson enables quoteless strings, multiline strings and optional
+	// commas of the Hjson grammar.
+	Hjson bool
+
+	// Python enables Python literals as generated by repr(),
+	// Bytes specifies how to convert bytes literals.
+	Python bool
+	Bytes  BytesEncoding
+
+	// OnInclude is called with the path and the content of each file
+	// read by the "@incl" directive, data is nil if the file cannot be
+	// read.
+	OnInclude func(path string, data []byte)
+
+	// OnEnv is called with the name of each environment variable read
+	// by the "@env" directive or FuncContext.LookupEnv, set reports
+	// whether the variable is present.
+	OnEnv func(name string, set bool)
+
+	// OnRef is called with the path of each reference which is resolved,
+	// and the path of the file which contains the reference.
+	OnRef func(filePath, path string)
+
+	// OnFunc is called with the name of each function which is called by
+	// the "@fn" directive, nondeterministic reports whether it is a builtin
+	// function which returns different results for each call.
+	OnFunc func(name string, nondeterministic bool)
+
+	// sourceMap enables recording the source of the output.
+	sourceMap bool
 }
 
-func parse(data []byte, importRoot string, depth int, enableEnv bool, funcMap map[string]interface{}) ([]byte, error) {
-	if depth > maxImportDepth {
-		return nil, errors.New("max import depth exceeded")
+// disallowed returns the features which cannot be used with the options,
+// including features of grammar modes which are not enabled.
+func (o *Options) disallowed() Feature {
+	disallowed := o.Disallowed
+	if !o.JSON5 {
+		disallowed |= FeatureJSON5
 	}
+	if !o.Hjson {
+		disallowed |= FeatureHjson
+	}
+	if !o.Python {
+		disallowed |= FeaturePython
+	}
+	if !o.JSON5 && !o.Python {
+		disallowed |= FeatureExtendedEscape
+	}
+	return disallowed
+}
 
-	doc := &JSON{
-		Buffer: b2s(data),
+func Parse(data []byte, opts Options) ([]byte, error) {
+	if opts.Context == nil {
+		opts.Context = context.Background()
 	}
-	if err := doc.Init(); err != nil {
-		return nil, err
+	out, _, err := parse(data, &opts, opts.FilePath, 0)
+	return out, err
+}
+
+// Validate checks whether data is valid extended JSON, without rewriting
+// the data or evaluating any directives.
+func Validate(data []byte, opts Options) error {
+	if json.Valid(data) {
+		return nil
 	}
-	if err := doc.Parse(); err != nil {
-		return nil, err
+	disallowed := opts.disallowed()
+	if !opts.EnableEnv {
+		disallowed |= FeatureEnv
 	}
-	if !doc.hasExtendedFeature() {
-		return data, nil
+	p := newParser(data, &opts, disallowed)
+	if _, err := p.document(); err != nil {
+		return err
 	}
+	return p.featureError()
+}
 
-	parser := &parser{
-		doc:          doc,
-		buf:          make([]byte, 0, len(data)),
-		root:         importRoot,
-		depth:        depth,
-		enableEnv:    enableEnv,
-		refTable:     make(map[string]int),
-		inputFuncMap: funcMap,
-		funcValMap:   make(map[string]reflect.Value),
+// parse converts data to strict JSON, the segments of the source map
+// are returned if it is enabled by opts.
+func parse(data []byte, opts *Options, filePath string, depth int) ([]byte, []segment, error) {
+	if depth > maxImportDepth {
+		return nil, nil, errors.New("max import depth exceeded")
+	}
+	var file *sourceFile
+	if opts.sourceMap {
+		file = &sourceFile{path: filePath, data: data}
 	}
-	parser.addFuncs(funcMap)
 
-	return parser.rewrite()
+	// Fast path: strict JSON needs no rewriting.
+	if json.Valid(data) {
+		return data, identitySegments(file), nil
+	}
+
+	p := newParser(data, opts, opts.disallowed())
+	p.filePath = filePath
+	p.depth = depth
+	p.file = file
+	if _, err := p.document(); err != nil {
+		return nil, nil, err
+	}
+	if err := p.featureError(); err != nil {
+		return nil, nil, err
+	}
+	if p.features == 0 {
+		return data, identitySegments(file), nil
+	}
+	if err := p.evaluate(); err != nil {
+		return nil, nil, err
+	}
+	if err := p.resolveReferences(); err != nil {
+		return nil, nil, err
+	}
+	return p.buf, p.segments, nil
 }
 
+// identitySegments maps output which is the same as the source file.
+func identitySegments(file *sourceFile) []segment {
+	if file == nil {
+		return nil
+	}
+	return []segment{{out: 0, file: file, offset: 0}}
+}
+
+// parser converts extended JSON to strict JSON in a single pass, values
+// are written to buf as they are scanned, directives are evaluated after
+// the whole data is checked to be valid.
 type parser struct {
-	doc *JSON
-	buf []byte
+	data []byte
+	pos  int
+	buf  []byte
+
+	opts       *Options
+	disallowed Feature
+	filePath   string
+	depth      int
+
+	// file is not nil if the source map is enabled, segments are
+	// sorted by the offsets in the output.
+	file     *sourceFile
+	segments []segment
+
+	// features are the extended features used in the data,
+	// bad is the first disallowed feature, which is used at badPos.
+	features Feature
+	bad      Feature
+	badPos   int
+
+	// err is the first error of converting values, it is reported
+	// after syntax errors, disallowed features and errors of the
+	// directives which appear before errPos.
+	err    error
+	errPos int
 
-	root  string
-	depth int
+	directives []directive
 
-	enableEnv bool
+	// failed records the objects which are neither a valid object nor
+	// a valid set, to not parse them again when backtracking.
+	failed map[int]error
 
-	refMark    string
-	refCounter int
-	refTable   map[string]int
-	refDag     dag
+	// tree is not nil when building the syntax tree, nodes and slices
+	// of children are allocated in chunks, the children of objects and
+	// arrays being parsed are pushed to stack.
+	tree       *Tree
+	nodes      []Node
+	stack      []*Node
+	childChunk []*Node
+	keys       map[string]string
 
-	inputFuncMap map[string]interface{}
-	funcValMap   map[string]reflect.Value
+	refs []refSite
+
+	funcValMap map[string]reflect.Value
+}
+
+func newParser(data []byte, opts *Options, disallowed Feature) *parser {
+	return &parser{
+		data:       data,
+		buf:        make([]byte, 0, len(data)),
+		opts:       opts,
+		disallowed: disallowed,
+	}
 }
 
-func (p *parser) text(n *node32) string {
-	return p.doc.text(n.token32)
+// parserState is a saved state of the parser, to backtrack when an
+// alternative syntax fails to parse.
+type parserState struct {
+	p        parser
+	comments []*Node
 }
 
-func (p *parser) rewrite() ([]byte, error) {
-	root := p.doc.AST()
-	if root.pegRule != ruleDocument {
-		return nil, errors.New("invalid JSON document")
+func (p *parser) save() parserState {
+	s := parserState{p: *p}
+	if p.tree != nil {
+		s.comments = p.tree.Comments
 	}
+	return s
+}
 
-	for n := root.up; n != nil; n = n.next {
-		switch n.pegRule {
-		case ruleSpacing:
-			continue
-		case ruleJSON:
-			if err := p.parseJSON(n); err != nil {
-				return nil, err
-			}
-		}
+func (p *parser) restore(s parserState) {
+	failed := p.failed
+	*p = s.p
+	p.failed = failed
+	if p.tree != nil {
+		p.tree.Comments = s.comments
 	}
+}
 
-	err := p.resolveReferences()
-	if err != nil {
-		return nil, err
+// use records that feature is used at offset i.
+func (p *parser) use(feature Feature, i int) {
+	p.features |= feature
+	if p.disallowed&feature != 0 && (p.bad == 0 || i < p.badPos) {
+		p.bad, p.badPos = feature, i
+	}
+}
+
+// featureError returns an error which names the first disallowed
+// feature used in the data.
+func (p *parser) featureError() error {
+	if p.bad == 0 {
+		return nil
+	}
+	pos := p.position(p.badPos)
+	if mode := featureModes[p.bad]; mode != "" {
+		return fmt.Errorf("%v is not allowed at %v, %s mode is not enabled", p.bad, pos, mode)
+	}
+	return fmt.Errorf("%v is not allowed at %v", p.bad, pos)
+}
+
+// fail records err of converting the value at offset i.
+func (p *parser) fail(err error, i int) {
+	if p.err == nil {
+		p.err, p.errPos = err, i
+	}
+}
+
+func (p *parser) syntaxError() error {
+	// Hjson syntax which is used before the error is likely the cause.
+	if p.bad == FeatureHjson && p.badPos < p.pos {
+		return p.featureError()
 	}
+	if p.pos >= len(p.data) {
+		return fmt.Errorf("parse error: unexpected end of input at %v", p.position(p.pos))
+	}
+	r, _ := utf8.DecodeRune(p.data[p.pos:])
+	return fmt.Errorf("parse error: unexpected %q at %v", r, p.position(p.pos))
+}
+
+func (p *parser) position(offset int) Position {
+	return position(p.data, offset)
+}
 
-	return p.buf, nil
+// column returns the column number of the byte offset.
+func (p *parser) column(offset int) int {
+	lineBegin := bytes.LastIndexByte(p.data[:offset], '\n') + 1
+	return utf8.RuneCount(p.data[lineBegin:offset]) + 1
 }
 
-func (p *parser) resolveReferences() error {
-	if len(p.refMark) == 0 {
+// node returns a new node if the parser is building the syntax tree,
+// else it returns nil.
+func (p *parser) node(kind NodeKind, begin, end int) *Node {
+	if p.tree == nil {
 		return nil
 	}
-	mark := p.refMark
-	markLen := len(mark) + 1
+	if len(p.nodes) == 0 {
+		p.nodes = make([]Node, nodeChunk)
+	}
+	node := &p.nodes[0]
+	p.nodes = p.nodes[1:]
+	*node = Node{Kind: kind, Begin: begin, End: end, Comma: -1}
+	return node
+}
 
-	resolved := make(map[int]string)
-	for path, seq := range p.refTable {
-		r := gjson.GetBytes(p.buf, path)
-		if !r.Exists() {
-			return fmt.Errorf("cannot resolve reference %s", path)
+const nodeChunk = 256
+
+// spacing skips whitespace and comments at the current offset.
+func (p *parser) spacing() {
+	data, i := p.data, p.pos
+	for i < len(data) {
+		c := data[i]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			i++
+			continue
 		}
+		if n := commentLen(data[i:]); n > 0 {
+			p.use(FeatureComments, i)
+			if p.tree != nil {
+				p.tree.Comments = append(p.tree.Comments, p.node(NodeComment, i, i+n))
+			}
+			i += n
+			continue
+		}
+		n := extendedSpaceLen(data[i:])
+		if n == 0 {
+			break
+		}
+		p.use(FeatureJSON5, i)
+		i += n
+	}
+	p.pos = i
+}
 
-		resolved[seq] = r.Raw
+// document parses the data, it returns the root node when building
+// the syntax tree.
+func (p *parser) document() (*Node, error) {
+	p.spacing()
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.data) {
+		return nil, p.syntaxError()
+	}
+	return root, nil
+}
 
-		pos := 0
-		for pos < len(r.Raw) {
-			idx := strings.Index(r.Raw[pos:], mark)
-			if idx < 0 {
+// value parses a value and the spacing after it.
+func (p *parser) value() (node *Node, err error) {
+	data, begin, out := p.data, p.pos, len(p.buf)
+	if begin >= len(data) {
+		return nil, p.syntaxError()
+	}
+	p.mapSource(out, begin)
+	switch c := data[begin]; {
+	case c == '{':
+		node, err = p.object()
+	case c == '[':
+		node, err = p.array()
+	case c == '(':
+		node, err = p.tuple()
+	case c == '"' || c == '\'':
+		if end := scanMultiline(data, begin); end > 0 {
+			node = p.multiline(begin, end)
+			break
+		}
+		end, ext := scanQuoted(data, begin)
+		if end < 0 {
+			return nil, p.syntaxError()
+		}
+		node = p.str(begin, end, ext)
+	default:
+		if isStringPrefix(data, begin) {
+			if end, ext := scanString(data, begin); end > 0 {
+				node = p.str(begin, end, ext)
 				break
 			}
-			end := idx + markLen + 1
-			for end < len(r.Raw) {
-				if r.Raw[end] >= '0' && r.Raw[end] <= '9' {
-					end++
-					continue
-				}
+		}
+		if c == '@' {
+			if d, ext, ok := scanDirective(data, begin); ok {
+				node = p.directive(d, ext)
 				break
 			}
-			refSeqStr := r.Raw[idx+markLen : end]
-			refSeq, _ := strconv.ParseInt(refSeqStr, 10, 32)
-			if int(refSeq) == seq {
-				return fmt.Errorf("cannot reference to self %s", path)
+		}
+		if c == 's' || c == 'f' {
+			var ok bool
+			if node, ok = p.pySet(); ok {
+				break
 			}
-			p.refDag.addEdge(int(refSeq), seq)
-			pos = end
 		}
+		node, err = p.literal()
+	}
+	if err != nil {
+		return nil, err
 	}
+	if node != nil {
+		// The syntax tree is decoded from the source data, the output is
+		// only kept for numbers which are converted.
+		if node.Kind == NodeNumber && !bytes.Equal(p.buf[out:], data[begin:node.End]) {
+			node.Value = string(p.buf[out:])
+		}
+		p.buf = p.buf[:out]
+	}
+	p.spacing()
+	return node, nil
+}
 
-	// resolving order
-	order := p.refDag.sort()
+// object parses an object, or a Python set which also begins with "{".
+func (p *parser) object() (*Node, error) {
+	data, begin := p.data, p.pos
+	if err := p.failed[begin]; err != nil {
+		return nil, err
+	}
+	p.pos++
+	p.spacing()
+	if p.pos < len(data) && data[p.pos] != '}' {
+		if kind, end, _ := scanKey(data, p.pos); kind == 0 || !isKeyEnd(data, end) {
+			return p.set(begin)
+		}
+	}
 
-	for _, seq := range order {
-		final := resolved[seq]
-		placeholder := `"` + p.referPlaceholder(seq) + `"`
-		p.refDag.visitNeighbors(seq, func(to int) {
-			resolved[to] = strings.Replace(resolved[to], placeholder, final, -1)
-		})
+	// Hjson quoteless strings may make the members a valid set.
+	saved := p.save()
+	node, err := p.members(begin)
+	if err == nil {
+		return node, nil
 	}
-	oldnew := make([]string, 0, 2*len(resolved))
-	for seq, text := range resolved {
-		placeholder := `"` + p.referPlaceholder(seq) + `"`
-		oldnew = append(oldnew, placeholder, text)
+	p.restore(saved)
+	if node, setErr := p.set(begin); setErr == nil {
+		return node, nil
 	}
-	var repl = strings.NewReplacer(oldnew...)
-	var buf bytes.Buffer
-	repl.WriteString(&buf, b2s(p.buf))
-	p.buf = buf.Bytes()
-	return nil
+	if p.failed == nil {
+		p.failed = make(map[int]error)
+	}
+	p.failed[begin] = err
+	return nil, err
 }
 
-func (p *parser) parseJSON(n *node32) (err error) {
-	n = n.up
-	switch n.pegRule {
-	case ruleObject:
-		if err = p.parseObject(n); err != nil {
-			return
+func (p *parser) members(begin int) (*Node, error) {
+	data := p.data
+	node := p.node(NodeObject, begin, -1)
+	mark := len(p.stack)
+	p.buf = append(p.buf, '{')
+	comma, noComma := -1, -1
+	for {
+		if p.pos >= len(data) {
+			return nil, p.syntaxError()
+		}
+		if data[p.pos] == '}' {
+			break
+		}
+		if noComma >= 0 { // Hjson optional comma
+			p.use(FeatureHjson, noComma)
+			p.buf = append(p.buf, ',')
+		}
+		child, err := p.member()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			p.stack = append(p.stack, child)
+		}
+		comma, noComma = p.comma(child)
+	}
+	if comma >= 0 {
+		p.use(FeatureTrailingComma, comma)
+	}
+	p.closing(node, mark, '}', comma)
+	return node, nil
+}
+
+func (p *parser) member() (*Node, error) {
+	data, begin := p.data, p.pos
+	kind, end, ext := scanKey(data, begin)
+	if kind == 0 {
+		return nil, p.syntaxError()
+	}
+	out := len(p.buf)
+	p.mapSource(out, begin)
+	p.key(kind, begin, end, ext)
+	var key string
+	if p.tree != nil && len(p.buf) > out {
+		var err error
+		if key, err = p.decodeKey(p.buf[out:]); err != nil {
+			return nil, err
 		}
-	case ruleArray:
-		if err = p.parseArray(n); err != nil {
+	}
+	p.pos = end
+	p.spacing()
+	if p.pos >= len(data) || data[p.pos] != ':' {
+		return nil, p.syntaxError()
+	}
+	p.buf = append(p.buf, ':')
+	p.pos++
+	p.spacing()
+	child, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if child != nil {
+		child.Key, child.KeyBegin, child.KeyEnd = key, begin, end
+	}
+	return child, nil
+}
+
+// decodeKey decodes a key in the output, keys without escapes are
+// interned as objects usually share the same keys.
+func (p *parser) decodeKey(data []byte) (string, error) {
+	content := data[1 : len(data)-1]
+	if bytes.IndexByte(content, '\\') >= 0 {
+		return unmarshalString(data)
+	}
+	if key, ok := p.keys[string(content)]; ok {
+		return key, nil
+	}
+	if p.keys == nil {
+		p.keys = make(map[string]string)
+	}
+	key := string(content)
+	p.keys[key] = key
+	return key, nil
+}
+
+func unmarshalString(data []byte) (s string, err error) {
+	err = json.Unmarshal(data, &s)
+	return
+}
+
+func (p *parser) key(kind, begin, end, ext int) {
+	data := p.data
+	switch kind {
+	case keyString:
+		p.appendStr(begin, end, ext, false)
+	case keyIdentifier:
+		p.use(FeatureUnquotedKey, begin)
+		p.use(FeatureJSON5, scanSimpleIdentifier(data, begin))
+		key, err := unquoteIdentifier(data[begin:end])
+		if err != nil {
+			p.fail(fmt.Errorf("%v at %v", err, p.position(begin)), begin)
 			return
 		}
-	case ruleString:
-		p.buf = append(p.buf, p.parseString(n, true)...)
-	case ruleTrue:
-		p.buf = append(p.buf, "true"...)
-	case ruleFalse:
-		p.buf = append(p.buf, "false"...)
-	case ruleNull:
-		p.buf = append(p.buf, "null"...)
-	case ruleNumber:
-		p.buf = append(p.buf, p.text(n)...)
-	case ruleDirective:
-		if err = p.parseDirective(n); err != nil {
+		p.buf = appendQuotedString(p.buf, key)
+	case keySimpleIdentifier:
+		p.use(FeatureUnquotedKey, begin)
+		if p.opts.JSON5 && isDigit(data[begin]) {
+			p.fail(fmt.Errorf("invalid identifier %q at %v", data[begin:end], p.position(begin)), begin)
 			return
 		}
+		p.buf = append(p.buf, '"')
+		p.buf = append(p.buf, p.parseKeyword(b2s(data[begin:end]))...)
+		p.buf = append(p.buf, '"')
+	case keyNumber:
+		p.numberKey(begin)
+	case keyQuoteless:
+		p.use(FeatureHjson, begin)
+		p.buf = appendQuotedString(p.buf, b2s(data[begin:end]))
 	}
-	return nil
 }
 
-func (p *parser) parseObject(n *node32) (err error) {
-	var preRule pegRule
-	for n := n.up; n != nil; n = n.next {
-		switch n.pegRule {
-		case ruleLWING:
-			p.buf = append(p.buf, '{')
-		case ruleRWING:
-			if preRule == ruleCOMMA {
-				p.buf = p.buf[:len(p.buf)-1]
-			}
-			p.buf = append(p.buf, '}')
-		case ruleCOLON:
-			p.buf = append(p.buf, ':')
-		case ruleCOMMA:
+// comma parses the comma after an element, it returns the offset of
+// the comma, or the offset where the comma is missing.
+func (p *parser) comma(elem *Node) (comma, noComma int) {
+	if p.pos >= len(p.data) || p.data[p.pos] != ',' {
+		return -1, p.pos
+	}
+	comma = p.pos
+	if elem != nil {
+		elem.Comma = comma
+	}
+	p.buf = append(p.buf, ',')
+	p.pos++
+	p.spacing()
+	return comma, -1
+}
+
+// closing parses the closing bracket of a container and writes c,
+// comma is the offset of the comma after the last element, or -1,
+// which is removed from the output.
+func (p *parser) closing(node *Node, mark int, c byte, comma int) {
+	if comma >= 0 {
+		p.buf = p.buf[:len(p.buf)-1]
+	}
+	p.buf = append(p.buf, c)
+	p.pos++
+	if node != nil {
+		node.End = p.pos
+		node.Children = p.children(mark)
+	}
+}
+
+// children pops the nodes pushed to the stack after mark, they are
+// copied to a slice of a shared chunk, whose capacity is limited to
+// not overwrite the others by appending.
+func (p *parser) children(mark int) []*Node {
+	n := len(p.stack) - mark
+	if n == 0 {
+		return nil
+	}
+	if len(p.childChunk) < n {
+		size := nodeChunk
+		if n > size {
+			size = n
+		}
+		p.childChunk = make([]*Node, size)
+	}
+	children := p.childChunk[:n:n]
+	copy(children, p.stack[mark:])
+	p.childChunk = p.childChunk[n:]
+	p.stack = p.stack[:mark]
+	return children
+}
+
+func (p *parser) array() (*Node, error) {
+	data := p.data
+	node := p.node(NodeArray, p.pos, -1)
+	mark := len(p.stack)
+	p.buf = append(p.buf, '[')
+	p.pos++
+	p.spacing()
+	comma, noComma := -1, -1
+	for {
+		if p.pos >= len(data) {
+			return nil, p.syntaxError()
+		}
+		if data[p.pos] == ']' {
+			break
+		}
+		if noComma >= 0 { // Hjson optional comma
+			p.use(FeatureHjson, noComma)
 			p.buf = append(p.buf, ',')
-		case ruleObjectKey:
-			p.buf = append(p.buf, p.parseObjectKey(n)...)
-		case ruleJSON:
-			err = p.parseJSON(n)
-			if err != nil {
-				return
-			}
 		}
-		preRule = n.pegRule
+		child, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			p.stack = append(p.stack, child)
+		}
+		comma, noComma = p.comma(child)
 	}
-	return nil
+	if comma >= 0 {
+		p.use(FeatureTrailingComma, comma)
+	}
+	p.closing(node, mark, ']', comma)
+	return node, nil
 }
 
-func (p *parser) parseObjectKey(n *node32) string {
-	n = n.up
-	switch n.pegRule {
-	case ruleSimpleIdentifier:
-		return `"` + string(p.doc.buffer[n.begin:n.end]) + `"`
-	case ruleString:
-		return p.parseString(n, true)
+// tuple converts a Python tuple to array.
+func (p *parser) tuple() (*Node, error) {
+	data := p.data
+	p.use(FeaturePython, p.pos)
+	node := p.node(NodeArray, p.pos, -1)
+	mark := len(p.stack)
+	p.buf = append(p.buf, '[')
+	p.pos++
+	p.spacing()
+	comma := -1
+	for n := 0; ; n++ {
+		if p.pos >= len(data) || data[p.pos] != ')' && n > 0 && comma < 0 {
+			return nil, p.syntaxError()
+		}
+		if data[p.pos] == ')' {
+			break
+		}
+		child, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			p.stack = append(p.stack, child)
+		}
+		comma, _ = p.comma(child)
 	}
-	return ""
+	p.closing(node, mark, ']', comma)
+	return node, nil
 }
 
-func (p *parser) parseArray(n *node32) (err error) {
-	var preRule pegRule
-	for n := n.up; n != nil; n = n.next {
-		switch n.pegRule {
-		case ruleLBRK:
-			p.buf = append(p.buf, '[')
-		case ruleRBRK:
-			if preRule == ruleCOMMA {
-				p.buf = p.buf[:len(p.buf)-1]
-			}
-			p.buf = append(p.buf, ']')
-		case ruleCOMMA:
-			p.buf = append(p.buf, ',')
-		case ruleJSON:
-			err = p.parseJSON(n)
-			if err != nil {
-				return
-			}
+// set converts a Python set to array, the current offset is after
+// the opening brace at begin.
+func (p *parser) set(begin int) (*Node, error) {
+	data := p.data
+	p.use(FeaturePython, begin)
+	node := p.node(NodeArray, begin, -1)
+	mark := len(p.stack)
+	p.buf = append(p.buf, '[')
+	comma := -1
+	for {
+		child, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			p.stack = append(p.stack, child)
+		}
+		comma, _ = p.comma(child)
+		if p.pos < len(data) && data[p.pos] == '}' {
+			break
+		}
+		if comma < 0 {
+			return nil, p.syntaxError()
 		}
-		preRule = n.pegRule
 	}
-	return nil
+	if comma >= 0 {
+		p.use(FeatureTrailingComma, comma)
+	}
+	p.closing(node, mark, ']', comma)
+	return node, nil
+}
+
+// str converts the string literal in data[begin:end], ext is the offset
+// of the first extended escape sequence, or -1.
+func (p *parser) str(begin, end, ext int) *Node {
+	node := p.node(NodeString, begin, end)
+	if str, ok := p.appendStr(begin, end, ext, node != nil); ok && node != nil {
+		node.Value = str
+	}
+	return node
+}
+
+// appendStr converts the string literal in data[begin:end] to the
+// output, the value of the string is returned only if decode is true.
+func (p *parser) appendStr(begin, end, ext int, decode bool) (string, bool) {
+	p.stringFeatures(begin, ext)
+	p.pos = end
+	if c := p.data[begin]; c == '"' && ext < 0 {
+		p.buf = append(p.buf, p.data[begin:end]...)
+		if !decode {
+			return "", false
+		}
+		str, err := p.unquote(begin, end)
+		if err != nil {
+			p.fail(err, begin)
+			return "", false
+		}
+		return str, true
+	} else if c == '\'' && !decode {
+		if s := p.data[begin+1 : end-1]; bytes.IndexByte(s, '\\') < 0 {
+			p.buf = appendQuotedString(p.buf, b2s(s))
+			return "", false
+		}
+	}
+	str, err := p.unquote(begin, end)
+	if err != nil {
+		p.fail(err, begin)
+		return "", false
+	}
+	p.buf = appendQuotedString(p.buf, str)
+	return str, true
+}
+
+func (p *parser) stringFeatures(begin, ext int) {
+	if c := p.data[begin]; c != '"' && c != '\'' {
+		p.use(FeaturePython, begin)
+		begin++
+	}
+	if p.data[begin] == '\'' {
+		p.use(FeatureSingleQuote, begin)
+	}
+	if ext >= 0 {
+		p.use(FeatureExtendedEscape, ext)
+	}
+}
+
+// unquote returns the value of the string literal in data[begin:end].
+func (p *parser) unquote(begin, end int) (str string, err error) {
+	isBytes := false
+	if c := p.data[begin]; c != '"' && c != '\'' {
+		isBytes = c == 'b' || c == 'B'
+		begin++
+	}
+	s := p.data[begin+1 : end-1]
+	switch {
+	case isBytes:
+		str, err = p.decodeBytes(s)
+	case p.opts.JSON5 && !p.opts.Python:
+		str, err = unquoteExtended(s)
+	case bytes.IndexByte(s, '\\') < 0:
+		str = string(s)
+	default:
+		var b []byte
+		b, err = unquoteString(s, false)
+		str = string(b)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%v at %v", err, p.position(begin))
+	}
+	return str, nil
 }
 
-var singleQuoteReplacer = strings.NewReplacer(`\'`, `'`, `"`, `\"`)
+// literal parses constants and numbers, in Hjson mode, other values
+// which begin with a valid character are quoteless strings.
+func (p *parser) literal() (*Node, error) {
+	data, begin := p.data, p.pos
+	if !isQuotelessStart(data[begin]) {
+		return nil, p.syntaxError()
+	}
+	if !p.opts.Hjson {
+		if kind, n := scanConstant(data, begin); n > 0 && isTokenEnd(data, begin+n) {
+			return p.constant(kind, begin, begin+n), nil
+		}
+		if t, ok := scanNumber(data, begin); ok && isTokenEnd(data, t.end) {
+			return p.number(begin, t), nil
+		}
+		return nil, p.syntaxError()
+	}
+	if kind, n := scanConstant(data, begin); n > 0 && isQuotelessEnd(data, begin+n) {
+		return p.constant(kind, begin, begin+n), nil
+	}
+	if t, ok := scanNumber(data, begin); ok && isQuotelessEnd(data, t.end) {
+		return p.number(begin, t), nil
+	}
+	return p.quoteless(), nil
+}
 
-func (p *parser) parseString(n *node32, escapeDoubleQuote bool) string {
-	n = n.up
-	switch n.pegRule {
-	case ruleSingleQuoteLiteral:
-		text := string(p.doc.buffer[n.begin+1 : n.end-1])
-		if escapeDoubleQuote {
-			text = singleQuoteReplacer.Replace(text)
+func (p *parser) constant(kind NodeKind, begin, end int) *Node {
+	switch p.data[begin] {
+	case 't':
+		p.buf = append(p.buf, "true"...)
+	case 'f':
+		p.buf = append(p.buf, "false"...)
+	case 'n':
+		p.buf = append(p.buf, "null"...)
+	case 'T':
+		p.use(FeaturePyConstants, begin)
+		p.buf = append(p.buf, "true"...)
+	case 'F':
+		p.use(FeaturePyConstants, begin)
+		p.buf = append(p.buf, "false"...)
+	case 'N':
+		p.use(FeaturePyConstants, begin)
+		p.buf = append(p.buf, "null"...)
+	}
+	p.pos = end
+	return p.node(kind, begin, end)
+}
+
+func (p *parser) number(begin int, t numberToken) *Node {
+	p.numberFeatures(t)
+	if err := p.appendNumber(t); err != nil {
+		p.fail(err, begin)
+	}
+	p.pos = t.end
+	return p.node(NodeNumber, begin, t.end)
+}
+
+func (p *parser) directive(d directive, ext int) *Node {
+	p.use(d.kind, d.begin)
+	p.stringFeatures(d.arg, ext)
+	p.pos = d.end
+	if p.tree == nil {
+		d.out = len(p.buf)
+		p.directives = append(p.directives, d)
+		return nil
+	}
+	node := p.node(NodeDirective, d.begin, d.end)
+	arg := p.node(NodeString, d.arg, d.argEnd)
+	if str, err := p.unquote(d.arg, d.argEnd); err != nil {
+		p.fail(err, d.arg)
+	} else {
+		arg.Value = str
+	}
+	node.Children = []*Node{arg}
+	return node
+}
+
+// directive is a directive found in the data, which is evaluated after
+// the whole data is parsed.
+type directive struct {
+	kind   Feature // FeatureEnv, FeatureInclude, FeatureRef or FeatureFunc
+	begin  int
+	end    int
+	arg    int // offset of the argument string
+	argEnd int
+	out    int // offset in the output to insert the value
+}
+
+// evaluate evaluates the directives in order and inserts their values
+// into the output.
+func (p *parser) evaluate() error {
+	if len(p.directives) == 0 {
+		return p.err
+	}
+	p.funcValMap = make(map[string]reflect.Value)
+	p.addFuncs(p.opts.FuncMap)
+
+	scanned, segments := p.buf, p.segments
+	p.buf = make([]byte, 0, len(scanned))
+	p.segments = nil
+	last := 0
+	for _, d := range p.directives {
+		if p.err != nil && d.begin > p.errPos {
+			break
+		}
+		p.segments = appendSegments(p.segments, segments, last, d.out, len(p.buf))
+		p.buf = append(p.buf, scanned[last:d.out]...)
+		last = d.out
+		p.mapSource(len(p.buf), d.begin)
+		if err := p.parseDirective(d); err != nil {
+			return err
 		}
-		return `"` + text + `"`
-	case ruleDoubleQuoteLiteral:
-		return p.text(n)
 	}
-	return ""
+	if p.err != nil {
+		return p.err
+	}
+	p.segments = appendSegments(p.segments, segments, last, len(scanned), len(p.buf))
+	p.buf = append(p.buf, scanned[last:]...)
+	return nil
 }
 
-func (p *parser) parseDirective(n *node32) (err error) {
-	n = n.up
-	switch n.pegRule {
-	case ruleEnv:
-		return p.parseEnv(n)
-	case ruleInclude:
-		return p.parseInclude(n)
-	case ruleRefer:
-		return p.parseRefer(n)
-	case ruleFunc:
-		return p.callFunction(n)
+func (p *parser) parseDirective(d directive) (err error) {
+	if err = p.opts.Context.Err(); err != nil {
+		return err
+	}
+	switch d.kind {
+	case FeatureEnv:
+		return p.parseEnv(d)
+	case FeatureInclude:
+		return p.parseInclude(d)
+	case FeatureRef:
+		return p.parseRefer(d)
+	case FeatureFunc:
+		return p.callFunction(d)
 	}
 	return nil
 }
 
-func (p *parser) parseEnv(n *node32) (err error) {
-	if !p.enableEnv {
+func (p *parser) parseEnv(d directive) (err error) {
+	if !p.opts.EnableEnv {
 		return errors.New("env feature is not enabled")
 	}
-	n = n.up
-	envName := p.parseString(n, true)
-	envName = envName[1 : len(envName)-1]
-	value := os.Getenv(envName)
+	envName, err := p.unquote(d.arg, d.argEnd)
+	if err != nil {
+		return err
+	}
+	value, set := os.LookupEnv(envName)
+	if p.opts.OnEnv != nil {
+		p.opts.OnEnv(envName, set)
+	}
 	b, _ := json.Marshal(value)
 	p.buf = append(p.buf, b...)
 	return nil
 }
 
-func (p *parser) parseInclude(n *node32) (err error) {
-	n = n.up
-	importPath := p.parseString(n, true)
-	importPath = filepath.Join(p.root, importPath[1:len(importPath)-1])
+func (p *parser) parseInclude(d directive) (err error) {
+	importPath, err := p.unquote(d.arg, d.argEnd)
+	if err != nil {
+		return err
+	}
+	importPath = filepath.Join(p.opts.IncludeRoot, importPath)
 	included, err := os.ReadFile(importPath)
+	if p.opts.OnInclude != nil {
+		p.opts.OnInclude(importPath, included)
+	}
 	if err != nil {
 		return
 	}
-	included, err = parse(included, p.root, p.depth+1, p.enableEnv, p.inputFuncMap)
+	included, segments, err := parse(included, p.opts, importPath, p.depth+1)
 	if err != nil {
 		return
 	}
+	p.segments = appendSegments(p.segments, segments, 0, len(included), len(p.buf))
 	p.buf = append(p.buf, included...)
 	return nil
 }
 
-func (p *parser) parseRefer(n *node32) (err error) {
-	n = n.up
-	jsonPath := p.parseString(n, true)
-	jsonPath = jsonPath[1 : len(jsonPath)-1]
-	seq, refId := p.getReferId(jsonPath)
-	p.buf = append(p.buf, '"')
-	p.buf = append(p.buf, refId...)
-	p.buf = append(p.buf, '"')
-	p.refDag.addVertex(seq)
-	return nil
-}
+// Feature is a bit mask of extended features.
+type Feature uint32
 
-func (p *parser) getReferId(path string) (int, string) {
-	if p.refMark == "" {
-		for {
-			mark := make([]byte, 16)
-			_, err := rand.Read(mark)
-			if err != nil {
-				panic(fmt.Sprintf("rand.Read got error %v", err))
-			}
-			str := hex.EncodeToString(mark)
-			if !strings.Contains(p.doc.Buffer, str) {
-				p.refMark = str
-				break
-			}
-		}
-	}
-	seq := p.refTable[path]
-	if seq == 0 {
-		p.refCounter++
-		seq = p.refCounter
-		p.refTable[path] = seq
-	}
-	placeholder := p.referPlaceholder(seq)
-	return seq, placeholder
+const (
+	FeatureComments Feature = 1 << iota
+	FeatureTrailingComma
+	FeatureSingleQuote
+	FeatureUnquotedKey
+	FeaturePyConstants
+	FeatureEnv
+	FeatureInclude
+	FeatureRef
+	FeatureFunc
+
+	// FeatureJSON5 represents syntax which is only available in the
+	// JSON5 grammar mode, it is not controlled by Options.Disallowed.
+	FeatureJSON5
+
+	// FeatureHjson represents syntax which is only available in the
+	// Hjson grammar mode, it is not controlled by Options.Disallowed.
+	FeatureHjson
+
+	// FeaturePython represents syntax which is only available in the
+	// Python literal mode, it is not controlled by Options.Disallowed.
+	FeaturePython
+
+	// FeatureExtendedEscape represents escape sequences which are
+	// available in either the JSON5 or the Python literal mode.
+	FeatureExtendedEscape
+
+	AllFeatures = FeatureComments | FeatureTrailingComma | FeatureSingleQuote |
+		FeatureUnquotedKey | FeaturePyConstants |
+		FeatureEnv | FeatureInclude | FeatureRef | FeatureFunc
+)
+
+var featureNames = map[Feature]string{
+	FeatureComments:       "comment",
+	FeatureTrailingComma:  "trailing comma",
+	FeatureSingleQuote:    "single quote string",
+	FeatureUnquotedKey:    "unquoted object key",
+	FeaturePyConstants:    "Python constant",
+	FeatureEnv:            "@env directive",
+	FeatureInclude:        "@incl directive",
+	FeatureRef:            "@ref directive",
+	FeatureFunc:           "@fn directive",
+	FeatureJSON5:          "JSON5 syntax",
+	FeatureHjson:          "Hjson syntax",
+	FeaturePython:         "Python syntax",
+	FeatureExtendedEscape: "extended escape sequence",
 }
 
-func (p *parser) referPlaceholder(n int) string {
-	return fmt.Sprintf("%s:%d", p.refMark, n)
+// featureModes maps features to the grammar modes which enable them.
+var featureModes = map[Feature]string{
+	FeatureJSON5:          "JSON5",
+	FeatureHjson:          "Hjson",
+	FeaturePython:         "Python",
+	FeatureExtendedEscape: "JSON5 or Python",
 }
 
-func (p *JSON) hasExtendedFeature() bool {
-	var preRule pegRule
-	for _, n := range p.Tokens() {
-		switch n.pegRule {
-		case ruleSingleQuoteLiteral,
-			ruleDirective, ruleEnv, ruleInclude, ruleRefer, ruleFunc,
-			ruleLongComment, ruleLineComment, rulePragma:
-			return true
-		case ruleRWING, ruleRBRK:
-			if preRule == ruleCOMMA {
-				return true
-			}
-		case ruleTrue:
-			if p.text(n) != "true" {
-				return true
-			}
-		case ruleFalse:
-			if p.text(n) != "false" {
-				return true
-			}
-		case ruleNull:
-			if p.text(n) != "null" {
-				return true
-			}
+func (f Feature) String() string {
+	var names []string
+	for x := FeatureComments; x <= FeatureExtendedEscape; x <<= 1 {
+		if f&x != 0 {
+			names = append(names, featureNames[x])
 		}
-		preRule = n.pegRule
 	}
-	return false
+	return strings.Join(names, "|")
+}
+
+// Position describes a location in the input data.
+type Position struct {
+	Offset int // byte offset, starting at 0
+	Line   int // line number, starting at 1
+	Column int // column number, starting at 1 (character count)
 }
 
-func (p *JSON) text(n token32) string {
-	return string(p.buffer[n.begin:n.end])
+func (p Position) String() string {
+	return fmt.Sprintf("%d:%d", p.Line, p.Column)
 }
 
 func b2s(b []byte) string {
@@ -0,0 +1,403 @@
+package parser
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"unicode/utf8"
+)
+
+// The parser generated from json.peg is the reference of the extended
+// grammar, pegParse converts data with the generated parser, it is used
+// to check the conformance of the hand-written scanner.
+//
+// Directives are not supported by the reference.
+
+var errPegDirective = errors.New("directive is not supported by the reference parser")
+
+func pegParse(data []byte, opts Options) ([]byte, error) {
+	if json.Valid(data) {
+		return data, nil
+	}
+	doc := &JSON{Buffer: string(data)}
+	if err := doc.Init(); err != nil {
+		return nil, err
+	}
+	if err := doc.Parse(); err != nil {
+		return nil, err
+	}
+	if !doc.hasExtendedFeature() {
+		return data, nil
+	}
+	if err := doc.checkFeatures(opts.disallowed()); err != nil {
+		return nil, err
+	}
+	p := &pegParser{doc: doc, opts: &opts}
+	for n := doc.AST().up; n != nil; n = n.next {
+		if n.pegRule == ruleJSON {
+			if err := p.parseJSON(n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return p.buf, nil
+}
+
+type pegParser struct {
+	doc  *JSON
+	buf  []byte
+	opts *Options
+}
+
+func (p *pegParser) text(n *node32) string {
+	return p.doc.text(n.token32)
+}
+
+func (p *pegParser) parseJSON(n *node32) (err error) {
+	n = n.up
+	switch n.pegRule {
+	case ruleObject:
+		return p.parseObject(n)
+	case ruleArray, ruleTuple, ruleSet:
+		return p.parseArray(n)
+	case rulePySet:
+		for c := n.up; c != nil; c = c.next {
+			if c.pegRule == ruleSet {
+				return p.parseArray(c)
+			}
+		}
+		p.buf = append(p.buf, "[]"...)
+	case ruleString:
+		var str string
+		if str, err = p.parseString(n); err != nil {
+			return
+		}
+		p.buf = append(p.buf, str...)
+	case ruleMultilineString:
+		indent := p.doc.position(n.begin).Column - 1
+		str := unquoteMultiline(runesToBytes(p.doc.buffer[n.begin+3:n.end-3]), indent)
+		p.buf = appendQuotedString(p.buf, str)
+	case ruleQuoteless:
+		str := strings.TrimRight(p.text(n), " \t")
+		p.buf = appendQuotedString(p.buf, str)
+	case ruleTrue:
+		p.buf = append(p.buf, "true"...)
+	case ruleFalse:
+		p.buf = append(p.buf, "false"...)
+	case ruleNull:
+		p.buf = append(p.buf, "null"...)
+	case ruleNumber:
+		return p.parseNumber(n)
+	case ruleDirective:
+		return errPegDirective
+	}
+	return nil
+}
+
+func (p *pegParser) parseObject(n *node32) (err error) {
+	var preRule pegRule
+	for n := n.up; n != nil; n = n.next {
+		switch n.pegRule {
+		case ruleLWING:
+			p.buf = append(p.buf, '{')
+		case ruleRWING:
+			if preRule == ruleCOMMA {
+				p.buf = p.buf[:len(p.buf)-1]
+			}
+			p.buf = append(p.buf, '}')
+		case ruleCOLON:
+			p.buf = append(p.buf, ':')
+		case ruleCOMMA:
+			p.buf = append(p.buf, ',')
+		case ruleObjectKey:
+			if preRule == ruleJSON { // Hjson optional comma
+				p.buf = append(p.buf, ',')
+			}
+			var key string
+			if key, err = p.parseObjectKey(n); err != nil {
+				return
+			}
+			p.buf = append(p.buf, key...)
+		case ruleJSON:
+			if err = p.parseJSON(n); err != nil {
+				return
+			}
+		}
+		preRule = n.pegRule
+	}
+	return nil
+}
+
+func (p *pegParser) parseObjectKey(n *node32) (string, error) {
+	n = n.up
+	switch n.pegRule {
+	case ruleSimpleIdentifier:
+		if p.opts.JSON5 && p.doc.buffer[n.begin] >= '0' && p.doc.buffer[n.begin] <= '9' {
+			return "", fmt.Errorf("invalid identifier %q at %v", p.text(n), p.doc.position(n.begin))
+		}
+		return `"` + (&parser{opts: p.opts}).parseKeyword(p.text(n)) + `"`, nil
+	case ruleNumberKey:
+		buf := p.buf
+		p.buf = nil
+		err := p.parseNumber(n.up)
+		key := string(appendQuotedString(nil, string(p.buf)))
+		p.buf = buf
+		return key, err
+	case ruleQuotelessKey:
+		return string(appendQuotedString(nil, p.text(n))), nil
+	case ruleIdentifier:
+		key, err := unquoteIdentifier(runesToBytes(p.doc.buffer[n.begin:n.end]))
+		if err != nil {
+			return "", fmt.Errorf("%v at %v", err, p.doc.position(n.begin))
+		}
+		return string(appendQuotedString(nil, key)), nil
+	case ruleString:
+		return p.parseString(n)
+	}
+	return "", nil
+}
+
+func (p *pegParser) parseArray(n *node32) (err error) {
+	var preRule pegRule
+	for n := n.up; n != nil; n = n.next {
+		switch n.pegRule {
+		case ruleLBRK, ruleLPAR, ruleLWING:
+			p.buf = append(p.buf, '[')
+		case ruleRBRK, ruleRPAR, ruleRWING:
+			if preRule == ruleCOMMA {
+				p.buf = p.buf[:len(p.buf)-1]
+			}
+			p.buf = append(p.buf, ']')
+		case ruleCOMMA:
+			p.buf = append(p.buf, ',')
+		case ruleJSON:
+			if preRule == ruleJSON { // Hjson optional comma
+				p.buf = append(p.buf, ',')
+			}
+			if err = p.parseJSON(n); err != nil {
+				return
+			}
+		}
+		preRule = n.pegRule
+	}
+	return nil
+}
+
+func (p *pegParser) parseString(n *node32) (string, error) {
+	if lit := n.up; lit.pegRule == ruleDoubleQuoteLiteral && !hasChild(lit, ruleExtendedEscape) {
+		return p.text(lit), nil
+	}
+	n = n.up
+	isBytes := false
+	if n.pegRule == ruleStringPrefix {
+		prefix := p.doc.buffer[n.begin]
+		isBytes = prefix == 'b' || prefix == 'B'
+		n = n.next
+	}
+	s := runesToBytes(p.doc.buffer[n.begin+1 : n.end-1])
+	var str string
+	var err error
+	switch {
+	case isBytes:
+		str, err = (&parser{opts: p.opts}).decodeBytes(s)
+	case p.opts.JSON5 && !p.opts.Python:
+		str, err = unquoteExtended(s)
+	default:
+		var b []byte
+		b, err = unquoteString(s, false)
+		str = string(b)
+	}
+	if err != nil {
+		return "", fmt.Errorf("%v at %v", err, p.doc.position(n.begin))
+	}
+	return string(appendQuotedString(nil, str)), nil
+}
+
+func (p *pegParser) parseNumber(n *node32) error {
+	neg := false
+	for n := n.up; n != nil; n = n.next {
+		switch n.pegRule {
+		case ruleMinus:
+			neg = true
+		case ruleHexNumber:
+			x, _ := new(big.Int).SetString(p.text(n)[2:], 16)
+			if neg {
+				p.buf = append(p.buf, '-')
+			}
+			p.buf = x.Append(p.buf, 10)
+		case ruleInfinity, ruleNaN, rulePyInfinity, rulePyNaN:
+			text := "NaN"
+			if n.pegRule == ruleInfinity || n.pegRule == rulePyInfinity {
+				text = "Infinity"
+				if neg {
+					text = "-Infinity"
+				}
+			}
+			switch p.opts.NonFinite {
+			case NonFiniteString:
+				p.buf = append(p.buf, `"`+text+`"`...)
+			case NonFiniteError:
+				return fmt.Errorf("non-finite number %s is not allowed at %v", text, p.doc.position(n.begin))
+			default:
+				p.buf = append(p.buf, "null"...)
+			}
+		case ruleIntegralPart:
+			if neg {
+				p.buf = append(p.buf, '-')
+			}
+			p.buf = append(p.buf, p.text(n)...)
+		case ruleFractionalPart:
+			if text := p.text(n); len(text) > 1 {
+				p.buf = append(p.buf, text...)
+			}
+		case ruleLeadingDecimal:
+			if neg {
+				p.buf = append(p.buf, '-')
+			}
+			p.buf = append(p.buf, '0')
+			p.buf = append(p.buf, p.text(n)...)
+		case ruleExponentPart:
+			p.buf = append(p.buf, p.text(n)...)
+		}
+	}
+	return nil
+}
+
+func hasChild(n *node32, rule pegRule) bool {
+	for n := n.up; n != nil; n = n.next {
+		if n.pegRule == rule {
+			return true
+		}
+	}
+	return false
+}
+
+func runesToBytes(s []rune) []byte {
+	return []byte(string(s))
+}
+
+// visitExtendedFeatures calls f for each token which uses an extended
+// feature, it stops the iteration when f returns false.
+func (p *JSON) visitExtendedFeatures(f func(feature Feature, n token32) bool) {
+	var pre token32
+	var noComma *token32
+	for _, n := range p.Tokens() {
+		switch n.pegRule {
+		case ruleSpacing, ruleWhitespace, ruleExtendedSpace, ruleExtendedSpaceChar,
+			ruleLongComment, ruleLineComment, rulePragma, ruleNoComma:
+		default:
+			// A missing comma is allowed only before the closing bracket.
+			if noComma != nil && n.pegRule != ruleRWING && n.pegRule != ruleRBRK {
+				if !f(FeatureHjson, *noComma) {
+					return
+				}
+			}
+			noComma = nil
+		}
+
+		var feature Feature
+		switch n.pegRule {
+		case ruleSpacing, ruleWhitespace:
+			continue
+		case ruleNoComma:
+			n := n
+			noComma = &n
+			continue
+		case ruleMultilineString, ruleQuoteless, ruleQuotelessKey:
+			feature = FeatureHjson
+		case ruleLongComment, ruleLineComment, rulePragma:
+			feature = FeatureComments
+		case ruleSingleQuoteLiteral:
+			feature = FeatureSingleQuote
+		case ruleSimpleIdentifier, ruleIdentifier:
+			feature = FeatureUnquotedKey
+		case rulePlus, ruleHexNumber, ruleInfinity, ruleNaN, ruleLeadingDecimal,
+			ruleExtendedSpace, ruleIdentifierEscape, ruleIdentifierUnicode:
+			feature = FeatureJSON5
+		case ruleTuple, ruleSet, rulePySet, ruleStringPrefix, rulePyInfinity, rulePyNaN, ruleNumberKey:
+			feature = FeaturePython
+		case ruleExtendedEscape:
+			feature = FeatureExtendedEscape
+		case ruleFractionalPart:
+			if n.end-n.begin == 1 {
+				feature = FeatureJSON5
+			}
+		case ruleEnv:
+			feature = FeatureEnv
+		case ruleInclude:
+			feature = FeatureInclude
+		case ruleRefer:
+			feature = FeatureRef
+		case ruleFunc:
+			feature = FeatureFunc
+		case ruleRWING, ruleRBRK:
+			if pre.pegRule == ruleCOMMA {
+				if !f(FeatureTrailingComma, pre) {
+					return
+				}
+			}
+		case ruleTrue:
+			if p.text(n) != "true" {
+				feature = FeaturePyConstants
+			}
+		case ruleFalse:
+			if p.text(n) != "false" {
+				feature = FeaturePyConstants
+			}
+		case ruleNull:
+			if p.text(n) != "null" {
+				feature = FeaturePyConstants
+			}
+		}
+		if feature != 0 && !f(feature, n) {
+			return
+		}
+		pre = n
+	}
+}
+
+func (p *JSON) hasExtendedFeature() (found bool) {
+	p.visitExtendedFeatures(func(_ Feature, _ token32) bool {
+		found = true
+		return false
+	})
+	return
+}
+
+func (p *JSON) checkFeatures(disallowed Feature) error {
+	var bad Feature
+	var badTok token32
+	p.visitExtendedFeatures(func(feature Feature, n token32) bool {
+		if disallowed&feature != 0 && (bad == 0 || n.begin < badTok.begin) {
+			bad, badTok = feature, n
+		}
+		return true
+	})
+	if bad != 0 {
+		if mode := featureModes[bad]; mode != "" {
+			return fmt.Errorf("%v is not allowed at %v, %s mode is not enabled", bad, p.position(badTok.begin), mode)
+		}
+		return fmt.Errorf("%v is not allowed at %v", bad, p.position(badTok.begin))
+	}
+	return nil
+}
+
+func (p *JSON) position(begin uint32) Position {
+	pos := Position{Line: 1, Column: 1}
+	for _, r := range p.buffer[:begin] {
+		pos.Offset += utf8.RuneLen(r)
+		if r == '\n' {
+			pos.Line++
+			pos.Column = 1
+		} else {
+			pos.Column++
+		}
+	}
+	return pos
+}
+
+func (p *JSON) text(n token32) string {
+	return string(p.buffer[n.begin:n.end])
+}
@@ -0,0 +1,104 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"unicode/utf8"
+)
+
+// BytesEncoding specifies how to convert Python bytes literals.
+type BytesEncoding int
+
+const (
+	BytesBase64 BytesEncoding = iota // convert to base64 encoded string
+	BytesText                        // convert to string, invalid UTF-8 is replaced by U+FFFD
+)
+
+// decodeBytes decodes the content of a bytes literal and converts it
+// to string as specified by Options.Bytes.
+func (p *parser) decodeBytes(s []byte) (string, error) {
+	b, err := unquoteString(s, true)
+	if err != nil {
+		return "", err
+	}
+	switch p.opts.Bytes {
+	case BytesText:
+		return strings.ToValidUTF8(string(b), string(utf8.RuneError)), nil
+	default:
+		return base64.StdEncoding.EncodeToString(b), nil
+	}
+}
+
+// pySet converts set() and frozenset() to array, it reports false if
+// there is no valid call at the current offset.
+func (p *parser) pySet() (*Node, bool) {
+	data, begin := p.data, p.pos
+	if bytes.HasPrefix(data[begin:], []byte("set()")) {
+		p.use(FeaturePython, begin)
+		p.buf = append(p.buf, "[]"...)
+		p.pos += len("set()")
+		return p.node(NodeArray, begin, p.pos), true
+	}
+	if !bytes.HasPrefix(data[begin:], []byte("frozenset(")) {
+		return nil, false
+	}
+
+	// The argument may not be a valid set, save the state to restore.
+	saved := p.save()
+	p.use(FeaturePython, begin)
+	p.pos += len("frozenset(")
+	p.spacing()
+	var node *Node
+	var err error
+	if p.pos < len(data) && data[p.pos] == '{' {
+		p.pos++
+		p.spacing()
+		if node, err = p.set(p.pos - 1); err == nil {
+			p.spacing()
+		}
+	} else {
+		p.buf = append(p.buf, "[]"...)
+		node = p.node(NodeArray, begin, -1)
+	}
+	if err != nil || p.pos >= len(data) || data[p.pos] != ')' {
+		p.restore(saved)
+		return nil, false
+	}
+	p.pos++
+	if node != nil {
+		node.Begin, node.End = begin, p.pos
+	}
+	return node, true
+}
+
+// parseKeyword converts Python constants used as object key to the
+// same keys generated by Python's json module.
+func (p *parser) parseKeyword(key string) string {
+	if p.opts.Python {
+		switch key {
+		case "True":
+			return "true"
+		case "False":
+			return "false"
+		case "None":
+			return "null"
+		}
+	}
+	return key
+}
+
+// numberKey converts a number used as object key to string.
+func (p *parser) numberKey(begin int) {
+	t, _ := scanNumber(p.data, begin)
+	p.numberFeatures(t)
+	p.use(FeaturePython, begin)
+	out := len(p.buf)
+	if err := p.appendNumber(t); err != nil {
+		p.fail(err, begin)
+		p.buf = p.buf[:out]
+		return
+	}
+	key := string(p.buf[out:])
+	p.buf = appendQuotedString(p.buf[:out], key)
+}
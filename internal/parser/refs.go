@@ -0,0 +1,312 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// refStandIn is written to the output in place of a reference until the
+// reference is resolved, it keeps the output valid JSON.
+const refStandIn = "null"
+
+// refSite is a reference in the output, the resolved value replaces
+// the stand-in at offset pos.
+type refSite struct {
+	pos  int
+	path string
+}
+
+func (p *parser) parseRefer(d directive) (err error) {
+	jsonPath, err := p.unquote(d.arg, d.argEnd)
+	if err != nil {
+		return err
+	}
+	p.refs = append(p.refs, refSite{pos: len(p.buf), path: jsonPath})
+	p.buf = append(p.buf, refStandIn...)
+	return nil
+}
+
+// resolveReferences replaces the references in the output by the values
+// they refer to. A value which contains references is resolved before
+// it is used, each referred path is resolved only once.
+func (p *parser) resolveReferences() error {
+	if len(p.refs) == 0 {
+		return nil
+	}
+	r := &refResolver{
+		buf:      p.buf,
+		refs:     p.refs,
+		segments: p.segments,
+		filePath: p.filePath,
+		targets:  make(map[string]*refTarget, len(p.refs)),
+	}
+	out := make([]byte, 0, len(p.buf))
+	var segments []segment
+	last := 0
+	for _, ref := range p.refs {
+		t, err := r.resolve(ref.path)
+		if err != nil {
+			return err
+		}
+		if p.opts.OnRef != nil {
+			p.opts.OnRef(p.filePath, ref.path)
+		}
+		// The value is mapped to the directive, unless it is found
+		// in the output.
+		segments = appendSegments(segments, p.segments, last, ref.pos+1, len(out))
+		out = append(out, p.buf[last:ref.pos]...)
+		segments = appendSegments(segments, t.segments, 0, len(t.value), len(out))
+		out = append(out, t.value...)
+		last = ref.pos + len(refStandIn)
+	}
+	p.segments = appendSegments(segments, p.segments, last, len(p.buf), len(out))
+	p.buf = append(out, p.buf[last:]...)
+	return nil
+}
+
+// containsRef tells whether there is a reference in buf[begin:end].
+func containsRef(refs []refSite, begin, end int) bool {
+	i := sort.Search(len(refs), func(i int) bool { return refs[i].pos >= begin })
+	return i < len(refs) && refs[i].pos < end
+}
+
+// RefCycleError is returned when references refer to each other in
+// a cycle, e.g. "a.b" refers to "c", which refers to "a.b".
+type RefCycleError struct {
+	// Path is the cycle of referred paths, the first one is repeated
+	// at the end.
+	Path []string
+
+	// File is the file which contains the references, it is empty if
+	// the file path is unknown.
+	File string
+}
+
+func (e *RefCycleError) Error() string {
+	msg := "circular reference " + strings.Join(e.Path, " -> ")
+	if e.File != "" {
+		msg += " in " + e.File
+	}
+	return msg
+}
+
+// refResolver resolves references on a tree of the output, the tree
+// is built lazily when the referred values are looked up.
+type refResolver struct {
+	buf      []byte
+	refs     []refSite
+	segments []segment
+	filePath string
+	targets  map[string]*refTarget
+	root     *refNode
+
+	// resolving is the stack of paths being resolved.
+	resolving []string
+}
+
+// refTarget is the value referred by a path, segments map the value
+// to the source if the source map is enabled.
+type refTarget struct {
+	resolving bool
+	value     []byte
+	segments  []segment
+}
+
+func (r *refResolver) resolve(path string) (*refTarget, error) {
+	if t := r.targets[path]; t != nil {
+		if t.resolving {
+			return nil, r.cycleError(path)
+		}
+		return t, nil
+	}
+	t := &refTarget{resolving: true}
+	r.targets[path] = t
+	r.resolving = append(r.resolving, path)
+
+	var err error
+	if isSimplePath(path) {
+		node := r.lookup(path)
+		if node == nil {
+			return nil, fmt.Errorf("cannot resolve reference %s", path)
+		}
+		err = r.splice(t, node.begin, node.end)
+	} else {
+		err = r.query(t, path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	t.resolving = false
+	r.resolving = r.resolving[:len(r.resolving)-1]
+	return t, nil
+}
+
+func (r *refResolver) cycleError(path string) error {
+	i := len(r.resolving) - 1
+	for r.resolving[i] != path {
+		i--
+	}
+	cycle := append([]string(nil), r.resolving[i:]...)
+	return &RefCycleError{Path: append(cycle, path), File: r.filePath}
+}
+
+// splice sets t to buf[begin:end] with the references in it resolved.
+func (r *refResolver) splice(t *refTarget, begin, end int) error {
+	i := sort.Search(len(r.refs), func(i int) bool { return r.refs[i].pos >= begin })
+	if i == len(r.refs) || r.refs[i].pos >= end {
+		t.value = r.buf[begin:end]
+		t.segments = appendSegments(nil, r.segments, begin, end, 0)
+		return nil
+	}
+	var value []byte
+	var segments []segment
+	last := begin
+	for ; i < len(r.refs) && r.refs[i].pos < end; i++ {
+		ref := r.refs[i]
+		x, err := r.resolve(ref.path)
+		if err != nil {
+			return err
+		}
+		segments = appendSegments(segments, r.segments, last, ref.pos+1, len(value))
+		value = append(value, r.buf[last:ref.pos]...)
+		segments = appendSegments(segments, x.segments, 0, len(x.value), len(value))
+		value = append(value, x.value...)
+		last = ref.pos + len(refStandIn)
+	}
+	t.segments = appendSegments(segments, r.segments, last, end, len(value))
+	t.value = append(value, r.buf[last:end]...)
+	return nil
+}
+
+// query resolves path which uses the extended syntax of gjson.
+// Values which are found in the output are spliced with references
+// resolved, other values, e.g. the results of modifiers, are used as is.
+func (r *refResolver) query(t *refTarget, path string) error {
+	res := gjson.GetBytes(r.buf, path)
+	if !res.Exists() {
+		return fmt.Errorf("cannot resolve reference %s", path)
+	}
+	if r.isSpan(res) {
+		return r.splice(t, res.Index, res.Index+len(res.Raw))
+	}
+	if res.Indexes == nil || !res.IsArray() {
+		t.value = []byte(res.Raw)
+		return nil
+	}
+
+	// Queries like "friends.#.first" produce arrays of the values
+	// found in the output.
+	t.value = []byte{'['}
+	var err error
+	res.ForEach(func(_, elem gjson.Result) bool {
+		if len(t.value) > 1 {
+			t.value = append(t.value, ',')
+		}
+		if !r.isSpan(elem) {
+			t.value = append(t.value, elem.Raw...)
+			return true
+		}
+		x := &refTarget{}
+		if err = r.splice(x, elem.Index, elem.Index+len(elem.Raw)); err != nil {
+			return false
+		}
+		t.segments = appendSegments(t.segments, x.segments, 0, len(x.value), len(t.value))
+		t.value = append(t.value, x.value...)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+	t.value = append(t.value, ']')
+	return nil
+}
+
+func (r *refResolver) isSpan(res gjson.Result) bool {
+	end := res.Index + len(res.Raw)
+	return res.Index > 0 && end <= len(r.buf) && b2s(r.buf[res.Index:end]) == res.Raw
+}
+
+// isSimplePath tells whether path is made of only keys and array
+// indexes, which are looked up in the tree of the output.
+func isSimplePath(path string) bool {
+	if path == "" || path[0] == '.' || path[len(path)-1] == '.' || strings.Contains(path, "..") {
+		return false
+	}
+	return !strings.ContainsAny(path, `*?#@|!\`)
+}
+
+func (r *refResolver) lookup(path string) *refNode {
+	if r.root == nil {
+		r.root = &refNode{begin: 0, end: len(r.buf)}
+	}
+	node := r.root
+	for node != nil && path != "" {
+		var key string
+		if i := strings.IndexByte(path, '.'); i >= 0 {
+			key, path = path[:i], path[i+1:]
+		} else {
+			key, path = path, ""
+		}
+		node = node.child(r.buf, key)
+	}
+	return node
+}
+
+// refNode is a value in the output, the children of objects and arrays
+// are indexed the first time they are looked up.
+type refNode struct {
+	begin, end int
+	expanded   bool
+	isArray    bool
+	keys       map[string]int
+	children   []*refNode
+}
+
+func (n *refNode) child(buf []byte, key string) *refNode {
+	if !n.expanded {
+		n.expand(buf)
+	}
+	if n.isArray {
+		i, err := strconv.Atoi(key)
+		if err != nil || i < 0 || i >= len(n.children) {
+			return nil
+		}
+		return n.children[i]
+	}
+	if i, ok := n.keys[key]; ok {
+		return n.children[i]
+	}
+	return nil
+}
+
+func (n *refNode) expand(buf []byte) {
+	n.expanded = true
+	raw := b2s(buf[n.begin:n.end])
+	switch trimmed := strings.TrimLeft(raw, " \t\r\n"); {
+	case strings.HasPrefix(trimmed, "["):
+		n.isArray = true
+	case !strings.HasPrefix(trimmed, "{"):
+		return
+	}
+	res := gjson.Result{Type: gjson.JSON, Raw: raw, Index: n.begin}
+	res.ForEach(func(key, value gjson.Result) bool {
+		if !n.isArray {
+			if n.keys == nil {
+				n.keys = make(map[string]int)
+			}
+			// The first one is used for duplicate keys, as gjson does.
+			if _, ok := n.keys[key.Str]; !ok {
+				n.keys[key.Str] = len(n.children)
+			}
+		}
+		n.children = append(n.children, &refNode{
+			begin: value.Index,
+			end:   value.Index + len(value.Raw),
+		})
+		return true
+	})
+}
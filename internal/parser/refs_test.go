@@ -0,0 +1,114 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestResolveReferences(t *testing.T) {
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{`{a: 1, b: @ref("a")}`, `{"a": 1, "b": 1}`},
+		{`{a: @ref("b"), b: @ref("c"), c: [1, 2]}`, `{"a": [1, 2], "b": [1, 2], "c": [1, 2]}`},
+		{`{a: {x: @ref("c.0")}, b: @ref("a"), c: ["y"]}`, `{"a": {"x": "y"}, "b": {"x": "y"}, "c": ["y"]}`},
+		{`{a: [{n: @ref("c")}, {n: 2}], b: @ref("a.#.n"), c: 1}`, `{"a": [{"n": 1}, {"n": 2}], "b": [1, 2], "c": 1}`},
+		{`{a: [1, 2, 3], b: @ref("a.#")}`, `{"a": [1, 2, 3], "b": 3}`},
+		{`{"a.b": 1, c: @ref("a\\.b")}`, `{"a.b": 1, "c": 1}`},
+		{`{a: 1, a: 2, b: @ref("a")}`, `{"a": 1, "a": 2, "b": 1}`},
+		{`{a: "null", b: @ref("a"), c: null}`, `{"a": "null", "b": "null", "c": null}`},
+		{`[{a: 1}, @ref("0.a"), @ref("0")]`, `[{"a": 1}, 1, {"a": 1}]`},
+	}
+	for _, tc := range testCases {
+		got, err := Parse([]byte(tc.data), Options{})
+		if err != nil {
+			t.Errorf("%s: %v", tc.data, err)
+			continue
+		}
+		var gotValue, wantValue interface{}
+		if err := json.Unmarshal(got, &gotValue); err != nil {
+			t.Errorf("%s: invalid output %s: %v", tc.data, got, err)
+			continue
+		}
+		json.Unmarshal([]byte(tc.want), &wantValue)
+		if !reflect.DeepEqual(gotValue, wantValue) {
+			t.Errorf("%s:\ngot  = %s\nwant = %s", tc.data, got, tc.want)
+		}
+	}
+}
+
+func TestResolveReferences_Errors(t *testing.T) {
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{`{a: @ref("b")}`, "cannot resolve reference b"},
+		{`{a: [1], b: @ref("a.1")}`, "cannot resolve reference a.1"},
+	}
+	for _, tc := range testCases {
+		_, err := Parse([]byte(tc.data), Options{})
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%s: got error %v, want %q", tc.data, err, tc.want)
+		}
+	}
+}
+
+func TestResolveReferences_Cycle(t *testing.T) {
+	testCases := []struct {
+		data string
+		want []string
+	}{
+		{`{a: @ref("a")}`, []string{"a", "a"}},
+		{`{a: {b: @ref("a")}}`, []string{"a", "a"}},
+		{`{a: {b: @ref("c")}, c: @ref("a.b")}`, []string{"c", "a.b", "c"}},
+		{`{a: @ref("b"), b: @ref("c"), c: {d: @ref("a")}}`, []string{"b", "c", "a", "b"}},
+		{`{x: @ref("a"), a: {y: @ref("b")}, b: [@ref("a.y")]}`, []string{"b", "a.y", "b"}},
+		{`{a: [{n: @ref("b")}], b: @ref("a.#.n")}`, []string{"b", "a.#.n", "b"}},
+	}
+	for _, tc := range testCases {
+		_, err := Parse([]byte(tc.data), Options{})
+		cycle, ok := err.(*RefCycleError)
+		if !ok {
+			t.Errorf("%s: got error %v, want cycle error", tc.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(cycle.Path, tc.want) {
+			t.Errorf("%s: got cycle %q, want %q", tc.data, cycle.Path, tc.want)
+		}
+	}
+}
+
+// benchRefData returns data with 2n references, the referred values
+// contain references to the previous values.
+func benchRefData(n int) []byte {
+	var buf strings.Builder
+	buf.WriteString("{values: [{id: 0, name: \"item 0\"},\n")
+	for i := 1; i < n; i++ {
+		fmt.Fprintf(&buf, "{id: %d, name: \"item %d\", prev: @ref(\"values.%d.name\")},\n", i, i, i-1)
+	}
+	buf.WriteString("],\nrefs: [")
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&buf, "@ref(\"values.%d\"),\n", n-1-i)
+	}
+	buf.WriteString("]}")
+	return []byte(buf.String())
+}
+
+func BenchmarkResolveReferences(b *testing.B) {
+	for _, n := range []int{100, 1000, 10000} {
+		data := benchRefData(n)
+		b.Run(fmt.Sprint(n), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := Parse(data, Options{}); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
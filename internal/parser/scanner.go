@@ -0,0 +1,487 @@
+package parser
+
+import (
+	"bytes"
+	"unicode/utf8"
+)
+
+// The scanner works on bytes of the input data, it matches the extended
+// grammar defined by json.peg, which is kept as the reference of the
+// syntax and is used to check the conformance of the scanner in tests.
+
+// skipSpacing returns the offset after the whitespace and comments at i.
+func skipSpacing(data []byte, i int) int {
+	for i < len(data) {
+		c := data[i]
+		if c == ' ' || c == '\t' || c == '\r' || c == '\n' {
+			i++
+			continue
+		}
+		n := commentLen(data[i:])
+		if n == 0 {
+			n = extendedSpaceLen(data[i:])
+		}
+		if n == 0 {
+			break
+		}
+		i += n
+	}
+	return i
+}
+
+// commentLen returns the length of the comment at the beginning of b,
+// or 0 if b does not begin with a comment.
+func commentLen(b []byte) int {
+	switch {
+	case len(b) == 0:
+		return 0
+	case b[0] == '#':
+		return lineLen(b)
+	case len(b) < 2 || b[0] != '/':
+		return 0
+	case b[1] == '/':
+		return lineLen(b)
+	case b[1] == '*':
+		if n := bytes.Index(b[2:], []byte("*/")); n >= 0 {
+			return n + 4
+		}
+	}
+	return 0
+}
+
+// lineLen returns the length of b before the first line break.
+func lineLen(b []byte) int {
+	for i, c := range b {
+		if c == '\n' || c == '\r' {
+			return i
+		}
+	}
+	return len(b)
+}
+
+// extendedSpaceLen returns the length of the JSON5 white space character
+// at the beginning of b, or 0 if b does not begin with one.
+func extendedSpaceLen(b []byte) int {
+	if len(b) == 0 {
+		return 0
+	}
+	if c := b[0]; c < utf8.RuneSelf {
+		if c == '\v' || c == '\f' {
+			return 1
+		}
+		return 0
+	}
+	r, size := utf8.DecodeRune(b)
+	if isExtendedSpace(r) {
+		return size
+	}
+	return 0
+}
+
+func isExtendedSpace(r rune) bool {
+	switch r {
+	case '\v', '\f', 0xa0, 0x1680, 0x2028, 0x2029, 0x202f, 0x205f, 0x3000, 0xfeff:
+		return true
+	}
+	return r >= 0x2000 && r <= 0x200a
+}
+
+// scanQuoted scans the string literal at i, which begins with a single
+// or double quote. It returns the end offset of the literal, or -1 if
+// there is no valid literal, and the offset of the first extended escape
+// sequence, or -1 if there is none.
+func scanQuoted(data []byte, i int) (end, ext int) {
+	q := data[i]
+	ext = -1
+	for i++; i < len(data); {
+		c := data[i]
+		switch {
+		case c == q:
+			return i + 1, ext
+		case c == '\n' || c == '\r':
+			return -1, ext
+		case c != '\\':
+			i++
+		default:
+			if n := escapeLen(data[i+1:], q); n > 0 {
+				i += 1 + n
+				continue
+			}
+			if i+1 >= len(data) || data[i+1] == 'u' {
+				return -1, ext
+			}
+			if ext < 0 {
+				ext = i
+			}
+			i += 1 + extendedEscapeLen(data[i+1:])
+		}
+	}
+	return -1, ext
+}
+
+// escapeLen returns the length of the escape sequence after a backslash
+// in a string literal quoted by q, or 0 if it is not a valid escape
+// sequence of the literal. Single quoted strings accept the escape
+// sequences of Python, which are a superset of JSON's.
+func escapeLen(b []byte, q byte) int {
+	if len(b) == 0 {
+		return 0
+	}
+	switch b[0] {
+	case 'b', 't', 'n', 'f', 'r', '"', '\\', '/':
+		return 1
+	case 'u':
+		return hexEscapeLen(b, 4)
+	}
+	if q == '"' {
+		return 0
+	}
+	switch c := b[0]; {
+	case c == 'v' || c == '\'':
+		return 1
+	case c == 'U':
+		return hexEscapeLen(b, 8)
+	case c == 'x':
+		return hexEscapeLen(b, 2)
+	case c >= '0' && c <= '7':
+		n := 1
+		for n < 3 && n < len(b) && b[n] >= '0' && b[n] <= '7' {
+			n++
+		}
+		return n
+	}
+	return 0
+}
+
+// hexEscapeLen returns size+1 if b is a letter followed by size hex
+// digits, else it returns 0.
+func hexEscapeLen(b []byte, size int) int {
+	if len(b) <= size || !isHexString(b[1:size+1]) {
+		return 0
+	}
+	return size + 1
+}
+
+// extendedEscapeLen returns the length of the escaped character after
+// a backslash, a line continuation of "\r\n" is treated as one character.
+func extendedEscapeLen(b []byte) int {
+	if len(b) >= 2 && b[0] == '\r' && b[1] == '\n' {
+		return 2
+	}
+	_, size := utf8.DecodeRune(b)
+	return size
+}
+
+// scanMultiline returns the end offset of the Hjson multiline string
+// at i, or -1 if there is no valid multiline string.
+func scanMultiline(data []byte, i int) int {
+	if !bytes.HasPrefix(data[i:], []byte("'''")) {
+		return -1
+	}
+	n := bytes.Index(data[i+3:], []byte("'''"))
+	if n < 0 {
+		return -1
+	}
+	return i + n + 6
+}
+
+// isStringPrefix reports whether data[i] is a Python string prefix
+// followed by a quote.
+func isStringPrefix(data []byte, i int) bool {
+	switch data[i] {
+	case 'b', 'B', 'u', 'U':
+		return i+1 < len(data) && (data[i+1] == '"' || data[i+1] == '\'')
+	}
+	return false
+}
+
+// scanString scans a string literal which may have a Python prefix,
+// it returns -1 as end if there is no valid literal.
+func scanString(data []byte, i int) (end, ext int) {
+	if isStringPrefix(data, i) {
+		i++
+	}
+	if data[i] != '"' && data[i] != '\'' {
+		return -1, -1
+	}
+	return scanQuoted(data, i)
+}
+
+// Kinds of numbers.
+const (
+	numDecimal        = iota // 1, 1.5, 1e3
+	numLeadingDecimal        // .5
+	numHex                   // 0x1F
+	numInfinity              // Infinity
+	numNaN                   // NaN
+	numPyInfinity            // inf
+	numPyNaN                 // nan
+)
+
+// numberToken holds the parts of a number literal.
+type numberToken struct {
+	kind  int
+	sign  byte // '+', '-', or 0
+	begin int  // offset after the sign
+	end   int
+	frac  int // offset of the fractional part, or -1
+	exp   int // offset of the exponent part, or -1
+}
+
+// scanNumber scans the number literal at i.
+func scanNumber(data []byte, i int) (t numberToken, ok bool) {
+	t.frac, t.exp = -1, -1
+	if i < len(data) && (data[i] == '-' || data[i] == '+') {
+		t.sign = data[i]
+		i++
+	}
+	t.begin = i
+	if i >= len(data) {
+		return t, false
+	}
+	rest := data[i:]
+	switch {
+	case len(rest) > 2 && rest[0] == '0' && (rest[1] == 'x' || rest[1] == 'X') && isHexDigit(rest[2]):
+		t.kind = numHex
+		i += 3
+		for i < len(data) && isHexDigit(data[i]) {
+			i++
+		}
+		t.end = i
+		return t, true
+	case bytes.HasPrefix(rest, []byte("Infinity")):
+		t.kind, t.end = numInfinity, i+8
+		return t, true
+	case bytes.HasPrefix(rest, []byte("NaN")):
+		t.kind, t.end = numNaN, i+3
+		return t, true
+	case bytes.HasPrefix(rest, []byte("inf")):
+		t.kind, t.end = numPyInfinity, i+3
+		return t, true
+	case bytes.HasPrefix(rest, []byte("nan")):
+		t.kind, t.end = numPyNaN, i+3
+		return t, true
+	case rest[0] == '0':
+		i++
+	case rest[0] >= '1' && rest[0] <= '9':
+		i = skipDigits(data, i+1)
+	case rest[0] == '.' && len(rest) > 1 && isDigit(rest[1]):
+		t.kind = numLeadingDecimal
+		i = skipDigits(data, i+1)
+	default:
+		return t, false
+	}
+	if t.kind == numDecimal && i < len(data) && data[i] == '.' {
+		t.frac = i
+		i = skipDigits(data, i+1)
+	}
+	if i < len(data) && (data[i] == 'e' || data[i] == 'E') {
+		j := i + 1
+		if j < len(data) && (data[j] == '+' || data[j] == '-') {
+			j++
+		}
+		if j < len(data) && isDigit(data[j]) {
+			t.exp = i
+			i = skipDigits(data, j)
+		}
+	}
+	t.end = i
+	return t, true
+}
+
+func skipDigits(data []byte, i int) int {
+	for i < len(data) && isDigit(data[i]) {
+		i++
+	}
+	return i
+}
+
+// scanConstant scans true, false, null or the Python constants at i,
+// it returns the length of the constant, or 0 if there is none.
+func scanConstant(data []byte, i int) (kind NodeKind, n int) {
+	for _, c := range constants {
+		if bytes.HasPrefix(data[i:], c.text) {
+			return c.kind, len(c.text)
+		}
+	}
+	return 0, 0
+}
+
+var constants = []struct {
+	kind NodeKind
+	text []byte
+}{
+	{NodeBool, []byte("true")},
+	{NodeBool, []byte("True")},
+	{NodeBool, []byte("false")},
+	{NodeBool, []byte("False")},
+	{NodeNull, []byte("null")},
+	{NodeNull, []byte("None")},
+}
+
+// isQuotelessEnd reports whether a value which ends at i can be
+// followed by a quoteless string, i.e. the value is not the beginning
+// of a quoteless string.
+func isQuotelessEnd(data []byte, i int) bool {
+	for i < len(data) && (data[i] == ' ' || data[i] == '\t') {
+		i++
+	}
+	if i >= len(data) {
+		return true
+	}
+	switch data[i] {
+	case '\r', '\n', ',', ']', ')', '}', '#':
+		return true
+	case '/':
+		return i+1 < len(data) && (data[i+1] == '/' || data[i+1] == '*')
+	}
+	return false
+}
+
+// isTokenEnd reports whether a constant or number which ends at i is
+// not followed by more characters of the same token.
+func isTokenEnd(data []byte, i int) bool {
+	return i >= len(data) || !isIdentifierByte(data[i]) && data[i] != '.'
+}
+
+// isQuotelessStart reports whether a quoteless string can begin with c.
+func isQuotelessStart(c byte) bool {
+	switch c {
+	case '{', '}', '[', ']', '(', ')', ',', ':', '"', '\'', ' ', '\t', '\r', '\n':
+		return false
+	}
+	return true
+}
+
+// Kinds of object keys.
+const (
+	keyString = iota + 1
+	keyIdentifier
+	keySimpleIdentifier
+	keyNumber
+	keyQuoteless
+)
+
+// scanKey scans the object key at i, it returns 0 as kind if there is
+// no valid key. Unquoted keys except Hjson quoteless keys must be
+// followed by a colon. ext is the offset of the first extended escape
+// sequence of a string key, or -1.
+func scanKey(data []byte, i int) (kind, end, ext int) {
+	if end, ext = scanString(data, i); end > 0 {
+		return keyString, end, ext
+	}
+	if end = scanIdentifier(data, i); end > 0 && isKeyEnd(data, end) {
+		return keyIdentifier, end, -1
+	}
+	if end = scanSimpleIdentifier(data, i); end > i && isKeyEnd(data, end) {
+		return keySimpleIdentifier, end, -1
+	}
+	if t, ok := scanNumber(data, i); ok && isKeyEnd(data, t.end) {
+		return keyNumber, t.end, -1
+	}
+	if end = scanQuotelessKey(data, i); end > i {
+		return keyQuoteless, end, -1
+	}
+	return 0, -1, -1
+}
+
+func scanQuotelessKey(data []byte, i int) int {
+	for ; i < len(data); i++ {
+		switch data[i] {
+		case '{', '}', '[', ']', ',', ':', '"', '\'', ' ', '\t', '\r', '\n':
+			return i
+		}
+	}
+	return i
+}
+
+func isKeyEnd(data []byte, i int) bool {
+	i = skipSpacing(data, i)
+	return i < len(data) && data[i] == ':'
+}
+
+func isIdentifierByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '$'
+}
+
+func scanSimpleIdentifier(data []byte, i int) int {
+	for i < len(data) && isIdentifierByte(data[i]) {
+		i++
+	}
+	return i
+}
+
+// scanIdentifier scans an ECMAScript identifier which contains unicode
+// escape sequences or non-ASCII characters, it returns -1 if there
+// is none.
+func scanIdentifier(data []byte, i int) int {
+	extended := false
+	for i < len(data) {
+		c := data[i]
+		switch {
+		case isIdentifierByte(c):
+			i++
+		case c == '\\':
+			if hexEscapeLen(data[i+1:], 4) == 0 || data[i+1] != 'u' {
+				goto end
+			}
+			extended = true
+			i += 6
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRune(data[i:])
+			if isExtendedSpace(r) {
+				goto end
+			}
+			extended = true
+			i += size
+		default:
+			goto end
+		}
+	}
+end:
+	if !extended {
+		return -1
+	}
+	return i
+}
+
+var directives = []struct {
+	kind   Feature
+	prefix []byte
+}{
+	{FeatureEnv, []byte("@env(")},
+	{FeatureInclude, []byte("@incl(")},
+	{FeatureRef, []byte("@ref(")},
+	{FeatureFunc, []byte("@fn(")},
+}
+
+// scanDirective scans the directive at i, whose argument is a string
+// literal. ext is the offset of the first extended escape sequence of
+// the argument, or -1.
+func scanDirective(data []byte, i int) (d directive, ext int, ok bool) {
+	for _, x := range directives {
+		if !bytes.HasPrefix(data[i:], x.prefix) {
+			continue
+		}
+		arg := i + len(x.prefix)
+		if arg >= len(data) {
+			return d, -1, false
+		}
+		end, ext := scanString(data, arg)
+		if end < 0 || end >= len(data) || data[end] != ')' {
+			return d, -1, false
+		}
+		return directive{kind: x.kind, begin: i, end: end + 1, arg: arg, argEnd: end}, ext, true
+	}
+	return d, -1, false
+}
+
+// position returns the position of the byte offset in data.
+func position(data []byte, offset int) Position {
+	lineBegin := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return Position{
+		Offset: offset,
+		Line:   bytes.Count(data[:lineBegin], []byte{'\n'}) + 1,
+		Column: utf8.RuneCount(data[lineBegin:offset]) + 1,
+	}
+}
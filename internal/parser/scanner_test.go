@@ -0,0 +1,306 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var conformanceData = []string{
+	`{"a": 1, "b": [true, false, null], "c": {"d": "e"}}`,
+	`{a: 1, b: [1, 2, 3,], 'c': 'it\'s', "d": "\u00e9\n",}`,
+	"// comment\n{/* block */ \"a\": 1 # pragma\n}",
+	`{"a": True, "b": False, "c": None, True: 1, None: 2}`,
+	`{a: .5, b: 5., c: +1, d: 0x1F, e: -0xff, f: Infinity, g: -Infinity, h: NaN, i: 1e5, j: -1.5E-3}`,
+	"[\u00a0 1,\u2028 2\ufeff]",
+	`{ümlåût: 1, \u0061b: 2, $_x: 3, a\u0062c: 4}`,
+	`{10twenty: 1}`,
+	`"\x41\v\0\'\
+"`,
+	`'\x41\101\U0001F600\u00e9\a\q'`,
+	`['\U00110000']`,
+	`"\xZZ"`,
+	"{\n  name: hello world\n  list: [\n    1\n    two\n    3\n  ]\n  md:\n    '''\n    First\n      Second\n    '''\n  'x': true story\n  y: 1 // not a comment\n}",
+	"{\"a\": 1\n\"b\": 2}",
+	"[1\n  2]",
+	`{a: b}`,
+	`(1, 2, (3,), ())`,
+	`{1, 2, 'a',}`,
+	`{'a', 'b'}`,
+	`[set(), frozenset(), frozenset({1, 2}), frozenset( {3} ), frozenset({})]`,
+	`{b'\x00\xff': b'abc', u'x': U"y", 'k': B'\101'}`,
+	`b'\u00e9'`,
+	`b'é'`,
+	`{-1: 2, 1.5: 3, +2: 4, .5: 5, -inf: 6, nan: 7}`,
+	`[inf, -inf, nan, +nan]`,
+	`[1, 2,, 3]`,
+	`{"a" 1}`,
+	`{"a": 1,,}`,
+	`[01]`,
+	`[1.5e]`,
+	`[0x]`,
+	`[-]`,
+	`[trueish, nullable, Nonesuch]`,
+	`{true: 1, null: 2, false: 3}`,
+	`"unterminated`,
+	`'unterminated`,
+	`'''unterminated`,
+	`{a: 1 /* unterminated`,
+	`[1, 2] trailing`,
+	``,
+	`   `,
+	"\"tab\there\"",
+	`"\u12"`,
+	`'\u12'`,
+	`'\U1234'`,
+	`"\uD83D\uDE00 \uD83D"`,
+	`'\uD83D\uDE00'`,
+	"{a:1,}\n",
+	`{"a": 1, /* comment */}`,
+	`[(1 2)]`,
+	`{a b: 1}`,
+	`{a\u0020: 1}`,
+	`{"a": [1, 2, 3}`,
+	"{\r\n  a: 1\r\n  b: '''\r\n  x\r\n  '''\r\n}",
+	`{a: frozenset({'a': 1})}`,
+	"{a: frozenset({'a': 1})\n}",
+	`{"a\\": 1}`,
+	`[@(1)]`,
+	`[#comment]`,
+	`[// comment
+	1]`,
+	`{'a': 'b'   }`,
+	`{"": ""}`,
+	`["\/", '\/']`,
+	`[1, 2] // trailing comment`,
+	`[1,2]` + "\v",
+	`[frozenset( {3} ), frozenset()]`,
+	"{\n  while:: true\n}",
+	"{\n  a: \"b\"\"\n}",
+}
+
+func conformanceCorpus(t *testing.T) []string {
+	corpus := append([]string(nil), conformanceData...)
+	files, _ := filepath.Glob("../../testdata/json5-tests/*/*")
+	files = append(files, "../../testdata.json")
+	for _, name := range files {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed read %s: %v", name, err)
+		}
+		if !strings.Contains(string(data), "@") {
+			corpus = append(corpus, string(data))
+		}
+	}
+	return corpus
+}
+
+// mutate returns data with some random bytes removed, duplicated or
+// replaced by characters which are significant in the grammar.
+func mutate(rnd *rand.Rand, data string) string {
+	const chars = "{}[](),:'\"\\/*#\n \t.-+0x1eEuUbBtTfFnN\u00a0\u00e9"
+	b := []byte(data)
+	for n := rnd.Intn(3) + 1; n > 0 && len(b) > 0; n-- {
+		i := rnd.Intn(len(b))
+		switch rnd.Intn(3) {
+		case 0:
+			b = append(b[:i], b[i+1:]...)
+		case 1:
+			b = append(b[:i], append([]byte{b[i]}, b[i:]...)...)
+		default:
+			c := chars[rnd.Intn(len(chars))]
+			b = append(b[:i], append([]byte{c}, b[i:]...)...)
+		}
+	}
+	return string(b)
+}
+
+// pegTrailingComma matches a trailing comma which the generated parser
+// does not report, when the closing bracket is followed by a comment or
+// an extended space character.
+var pegTrailingComma = regexp.MustCompile(`,\s*[\]}]\s*(//|/\*|#|[\v\x{a0}\x{2028}\x{2029}\x{feff}])`)
+
+// pegQuoteless tells whether the generated parser parsed or failed on
+// a quoteless string outside Hjson mode. The scanner only parses quoteless
+// strings in Hjson mode, else it reports the bad token, or accepts a
+// number which is followed by an extended space character.
+func pegQuoteless(data string, pegErr, err error, opts Options) bool {
+	if opts.Hjson || pegErr == nil {
+		return false
+	}
+	msg := pegErr.Error()
+	if strings.HasPrefix(msg, "Hjson syntax is not allowed") || strings.Contains(msg, "near Quoteless") {
+		return true
+	}
+	if err == nil || !strings.HasPrefix(err.Error(), "parse error") && !strings.HasPrefix(err.Error(), "Hjson syntax") {
+		return false
+	}
+	opts.Hjson = true
+	_, err = Parse([]byte(data), opts)
+	return err == nil || !strings.HasPrefix(err.Error(), "parse error")
+}
+
+func TestScannerConformance(t *testing.T) {
+	options := []Options{
+		{},
+		{JSON5: true},
+		{Hjson: true},
+		{Python: true},
+		{JSON5: true, Hjson: true, Python: true, NonFinite: NonFiniteString, Bytes: BytesText},
+		{JSON5: true, NonFinite: NonFiniteError},
+		{Disallowed: AllFeatures, JSON5: true},
+		{Disallowed: FeatureTrailingComma | FeatureSingleQuote, Python: true},
+	}
+	check := func(data string) {
+		if !utf8.ValidString(data) {
+			// The generated parser replaces invalid UTF-8 by U+FFFD.
+			return
+		}
+		if pegTrailingComma.MatchString(data) {
+			return
+		}
+		for _, opts := range options {
+			want, wantErr := pegParse([]byte(data), opts)
+			if wantErr == errPegDirective {
+				return
+			}
+			got, err := Parse([]byte(data), opts)
+			switch {
+			case pegQuoteless(data, wantErr, err, opts):
+			case (err == nil) != (wantErr == nil):
+				t.Errorf("%q with %+v:\ngot  = %s, %v\nwant = %s, %v", data, opts, got, err, want, wantErr)
+			case wantErr != nil && !strings.Contains(wantErr.Error(), "parse error") && err.Error() != wantErr.Error():
+				t.Errorf("%q with %+v:\ngot error  = %v\nwant error = %v", data, opts, err, wantErr)
+			case err == nil && string(got) != string(want):
+				t.Errorf("%q with %+v:\ngot  = %s\nwant = %s", data, opts, got, want)
+			}
+		}
+	}
+
+	corpus := conformanceCorpus(t)
+	for _, data := range corpus {
+		check(data)
+	}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 20000; i++ {
+		check(mutate(rnd, corpus[rnd.Intn(len(corpus))]))
+	}
+}
+
+func TestScannerErrorPosition(t *testing.T) {
+	testCases := []struct {
+		data string
+		opts Options
+		err  string
+	}{
+		{`[1 2]`, Options{}, "Hjson syntax is not allowed at 1:4, Hjson mode is not enabled"},
+		{`{a:1 b:2}`, Options{}, "Hjson syntax is not allowed at 1:6, Hjson mode is not enabled"},
+		{`{"a": tru}`, Options{}, "parse error: unexpected 't' at 1:7"},
+		{"{\n  \"a\": 1,\n  \"b\": nul\n}", Options{}, "parse error: unexpected 'n' at 3:8"},
+		{`[1, 2x]`, Options{}, "parse error: unexpected '2' at 1:5"},
+		{`[truex]`, Options{JSON5: true}, "parse error: unexpected 't' at 1:2"},
+		{`{"a": hello}`, Options{}, "parse error: unexpected 'h' at 1:7"},
+		{`{"a": hello}`, Options{Hjson: true}, "parse error: unexpected end of input at 1:13"},
+	}
+	for _, tc := range testCases {
+		_, err := Parse([]byte(tc.data), tc.opts)
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("%q: got error %v, want %q", tc.data, err, tc.err)
+		}
+	}
+}
+
+var benchExtendedJSON = func() []byte {
+	var buf bytes.Buffer
+	buf.WriteString("// generated data\n{\n  items: [\n")
+	for i := 0; i < 1000; i++ {
+		buf.WriteString(`    {id: 12345, 'name': 'some name', "tags": ['a', "b", 'c',], ok: True, score: +1.5e3, mask: 0xff}, /* item */` + "\n")
+	}
+	buf.WriteString("  ],\n}\n")
+	return buf.Bytes()
+}()
+
+var benchStrictJSON = func() []byte {
+	var buf bytes.Buffer
+	if err := json.Compact(&buf, mustParse(benchExtendedJSON)); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}()
+
+func mustParse(data []byte) []byte {
+	out, err := Parse(data, Options{JSON5: true, Python: true})
+	if err != nil {
+		panic(err)
+	}
+	return out
+}
+
+func BenchmarkParse_Extended(b *testing.B) {
+	opts := Options{JSON5: true, Python: true}
+	b.SetBytes(int64(len(benchExtendedJSON)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(benchExtendedJSON, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParse_Extended_Peg(b *testing.B) {
+	opts := Options{JSON5: true, Python: true}
+	b.SetBytes(int64(len(benchExtendedJSON)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := pegParse(benchExtendedJSON, opts); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// The strict benchmarks skip the json.Valid fast path of Parse, to
+// measure the scanner itself.
+
+func BenchmarkScan_Strict(b *testing.B) {
+	opts := &Options{}
+	b.SetBytes(int64(len(benchStrictJSON)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		p := newParser(benchStrictJSON, opts, opts.disallowed())
+		if _, err := p.document(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScan_Strict_Peg(b *testing.B) {
+	b.SetBytes(int64(len(benchStrictJSON)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		doc := &JSON{Buffer: string(benchStrictJSON)}
+		if err := doc.Init(); err != nil {
+			b.Fatal(err)
+		}
+		if err := doc.Parse(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkScan_Strict_EncodingJSON(b *testing.B) {
+	var buf bytes.Buffer
+	b.SetBytes(int64(len(benchStrictJSON)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := json.Compact(&buf, benchStrictJSON); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
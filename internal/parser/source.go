@@ -0,0 +1,109 @@
+package parser
+
+import (
+	"context"
+	"sort"
+)
+
+// ParseWithSourceMap is like Parse, but it also returns the map from
+// offsets in the output to the source files, including the files which
+// are included and the values which are referred.
+func ParseWithSourceMap(data []byte, opts Options) ([]byte, *SourceMap, error) {
+	if opts.Context == nil {
+		opts.Context = context.Background()
+	}
+	opts.sourceMap = true
+	out, segments, err := parse(data, &opts, opts.FilePath, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+	return out, &SourceMap{segments: segments}, nil
+}
+
+// NewSourceMap returns the source map of data read from the file at
+// path, which is used as is as the output.
+func NewSourceMap(path string, data []byte) *SourceMap {
+	return &SourceMap{segments: identitySegments(&sourceFile{path: path, data: data})}
+}
+
+// Source is a location in a source file.
+type Source struct {
+	// File is the path of the file, it is empty for data which is not
+	// read from a file.
+	File string
+	Position
+}
+
+func (s Source) String() string {
+	if s.File == "" {
+		return s.Position.String()
+	}
+	return s.File + ":" + s.Position.String()
+}
+
+// SourceMap maps offsets in the output of ParseWithSourceMap to the
+// source files.
+type SourceMap struct {
+	segments []segment
+}
+
+// segment maps the output from out, up to the next segment, to the
+// source from offset in file. Segments begin at each value and key.
+type segment struct {
+	out    int
+	file   *sourceFile
+	offset int
+}
+
+type sourceFile struct {
+	path string
+	data []byte
+}
+
+// Lookup returns the source of the output at offset out, it reports
+// false if the offset is not mapped.
+func (m *SourceMap) Lookup(out int) (Source, bool) {
+	if m == nil {
+		return Source{}, false
+	}
+	i := sort.Search(len(m.segments), func(i int) bool { return m.segments[i].out > out }) - 1
+	if i < 0 {
+		return Source{}, false
+	}
+	s := m.segments[i]
+	offset := s.offset + out - s.out
+	if offset > len(s.file.data) {
+		offset = len(s.file.data)
+	}
+	return Source{File: s.file.path, Position: position(s.file.data, offset)}, true
+}
+
+// mapSource records that the output at out is scanned from the source
+// at offset, if the source map is enabled.
+func (p *parser) mapSource(out, offset int) {
+	if p.file != nil {
+		p.segments = append(p.segments, segment{out: out, file: p.file, offset: offset})
+	}
+}
+
+// appendSegments appends the segments of src which map the output in
+// [begin, end) to dst, where the output is moved to base. The segment
+// which covers begin is moved to base.
+func appendSegments(dst, src []segment, begin, end, base int) []segment {
+	if len(src) == 0 || begin >= end {
+		return dst
+	}
+	i := sort.Search(len(src), func(i int) bool { return src[i].out > begin }) - 1
+	if i >= 0 {
+		s := src[i]
+		s.offset += begin - s.out
+		s.out = base
+		dst = append(dst, s)
+	}
+	for i++; i < len(src) && src[i].out < end; i++ {
+		s := src[i]
+		s.out += base - begin
+		dst = append(dst, s)
+	}
+	return dst
+}
@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestParseWithSourceMap(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.json": `{
+  // comment
+  a: {x: 1},
+  inc: @incl("inc.json"),
+  strict: @incl("strict.json"),
+  r: @ref("a"),
+  q: @ref("inc.#.k"),
+  n: @ref("inc.#"),
+}`,
+		"inc.json":    "[\n  {k: 1},\n  {k: @ref(\"0.k\")},\n]",
+		"strict.json": "{\n\"s\": true}",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mainPath := filepath.Join(dir, "main.json")
+	out, sources, err := ParseWithSourceMap([]byte(files["main.json"]), Options{
+		FilePath:    mainPath,
+		IncludeRoot: dir,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tree, err := ParseTree(out, Options{})
+	if err != nil {
+		t.Fatalf("invalid output %s: %v", out, err)
+	}
+
+	testCases := []struct {
+		path string // path of the node, ending with ":" for the key
+		file string
+		pos  string
+	}{
+		{"a:", "main.json", "3:3"},
+		{"a.x", "main.json", "3:10"},
+		{"inc", "inc.json", "1:1"},
+		{"inc.1.k:", "inc.json", "3:4"},
+		{"inc.1.k", "inc.json", "2:7"},
+		{"strict.s:", "strict.json", "2:1"},
+		{"strict.s", "strict.json", "2:6"},
+		{"r:", "main.json", "6:3"},
+		{"r", "main.json", "3:6"},
+		{"r.x", "main.json", "3:10"},
+		{"q", "main.json", "7:6"},
+		{"q.1", "inc.json", "2:7"},
+		{"n", "main.json", "8:6"},
+	}
+	for _, tc := range testCases {
+		node, isKey := findNode(t, tree.Root, tc.path)
+		if node == nil {
+			continue
+		}
+		offset := node.Begin
+		if isKey {
+			offset = node.KeyBegin
+		}
+		got, ok := sources.Lookup(offset)
+		want := filepath.Join(dir, tc.file) + ":" + tc.pos
+		if !ok || got.String() != want {
+			t.Errorf("%s: got source %v, want %v", tc.path, got, want)
+		}
+	}
+}
+
+func findNode(t *testing.T, node *Node, path string) (*Node, bool) {
+	isKey := strings.HasSuffix(path, ":")
+	for _, key := range strings.Split(strings.TrimSuffix(path, ":"), ".") {
+		var child *Node
+		for i, x := range node.Children {
+			if x.Key == key || node.Kind == NodeArray && strconv.Itoa(i) == key {
+				child = x
+			}
+		}
+		if child == nil {
+			t.Errorf("%s: node not found", path)
+			return nil, false
+		}
+		node = child
+	}
+	return node, isKey
+}
@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"fmt"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// unquoteString decodes the content of a quoted string literal.
+// It accepts the escape sequences of JSON and Python, which are a
+// superset of the escapes allowed in single quoted strings.
+// If isBytes is true, the content is decoded as a Python bytes literal,
+// else the result is UTF-8 encoded text.
+func unquoteString(s []byte, isBytes bool) ([]byte, error) {
+	var b []byte
+	writeRune := func(r rune) {
+		if isBytes {
+			b = append(b, byte(r))
+		} else {
+			b = append(b, string(r)...)
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			if isBytes && s[i] >= utf8.RuneSelf {
+				return nil, fmt.Errorf("bytes can only contain ASCII characters")
+			}
+			b = append(b, s[i])
+			continue
+		}
+		i++
+		c, size := utf8.DecodeRune(s[i:])
+		switch c {
+		case '\\', '\'', '"', '/':
+			b = append(b, byte(c))
+		case 'a':
+			b = append(b, '\a')
+		case 'b':
+			b = append(b, '\b')
+		case 'f':
+			b = append(b, '\f')
+		case 'n':
+			b = append(b, '\n')
+		case 'r':
+			b = append(b, '\r')
+		case 't':
+			b = append(b, '\t')
+		case 'v':
+			b = append(b, '\v')
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			x := 0
+			j := i
+			for ; j < len(s) && j < i+3 && s[j] >= '0' && s[j] <= '7'; j++ {
+				x = x*8 + int(s[j]-'0')
+			}
+			if isBytes && x > 0xff {
+				return nil, fmt.Errorf("invalid octal escape sequence \\%s", s[i:j])
+			}
+			writeRune(rune(x))
+			i = j - 1
+		case 'x':
+			if i+2 >= len(s) || !isHexDigit(s[i+1]) || !isHexDigit(s[i+2]) {
+				return nil, fmt.Errorf("invalid escape sequence \\x")
+			}
+			writeRune(rune(hexValue(s[i+1 : i+3])))
+			i += 2
+		case 'u', 'U':
+			size := 4
+			if c == 'U' {
+				size = 8
+			}
+			if isBytes {
+				b = append(b, '\\', byte(c))
+				continue
+			}
+			if i+size >= len(s) || !isHexString(s[i+1:i+1+size]) {
+				return nil, fmt.Errorf("invalid escape sequence \\%c", c)
+			}
+			r := rune(hexValue(s[i+1 : i+1+size]))
+			i += size
+			if utf16.IsSurrogate(r) && i+6 < len(s) && s[i+1] == '\\' && s[i+2] == 'u' && isHexString(s[i+3:i+7]) {
+				if r2 := utf16.DecodeRune(r, rune(hexValue(s[i+3:i+7]))); r2 != utf8.RuneError {
+					r = r2
+					i += 6
+				}
+			}
+			if r > utf8.MaxRune {
+				return nil, fmt.Errorf("invalid escape sequence \\%c%s", c, s[i-size+1:i+1])
+			}
+			b = append(b, string(r)...)
+		case '\r':
+			if i+1 < len(s) && s[i+1] == '\n' {
+				i++
+			}
+		case '\n', '\u2028', '\u2029':
+			// line continuation
+			i += size - 1
+		default:
+			// Python keeps unrecognized escape sequences unchanged.
+			b = append(b, '\\')
+			b = append(b, s[i:i+size]...)
+			i += size - 1
+		}
+	}
+	return b, nil
+}
+
+func isHexString(s []byte) bool {
+	for _, c := range s {
+		if !isHexDigit(c) {
+			return false
+		}
+	}
+	return true
+}
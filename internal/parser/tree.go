@@ -0,0 +1,131 @@
+package parser
+
+// NodeKind is the kind of value represented by a Node.
+type NodeKind int
+
+// Kinds of Node, Python tuples and sets are represented as arrays,
+// multiline and quoteless strings are represented as strings.
+const (
+	NodeObject NodeKind = iota + 1
+	NodeArray
+	NodeString
+	NodeNumber
+	NodeBool
+	NodeNull
+	NodeDirective
+	NodeComment
+)
+
+// Tree is the syntax tree of an extended JSON document.
+type Tree struct {
+	Root     *Node
+	Comments []*Node
+
+	// Features are the extended features used in the document.
+	Features Feature
+}
+
+// Node is a value in the syntax tree of an extended JSON document.
+// Offsets are byte offsets in the source data, spacing and comments
+// around the value are not included in [Begin, End).
+//
+// Children are members of an object, elements of an array, or the
+// argument string of a directive.
+type Node struct {
+	Kind     NodeKind
+	Begin    int
+	End      int
+	Children []*Node
+
+	// Value is the decoded value of a string, or the strict JSON of a
+	// number which is converted, such as a hexadecimal number.
+	Value string
+
+	// Key, KeyBegin and KeyEnd are set for object members.
+	Key      string
+	KeyBegin int
+	KeyEnd   int
+
+	// Comma is the offset of the comma following an object member or
+	// an array element, it is -1 if there is no comma.
+	Comma int
+}
+
+// ParseTree parses data and returns the syntax tree.
+// Directives are not evaluated.
+func ParseTree(data []byte, opts Options) (*Tree, error) {
+	p := newParser(data, &opts, opts.disallowed())
+	p.tree = &Tree{}
+	root, err := p.document()
+	if err != nil {
+		return nil, err
+	}
+	if err = p.featureError(); err != nil {
+		return nil, err
+	}
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.tree.Root = root
+	p.tree.Features = p.features
+	return p.tree, nil
+}
+
+// Literal returns the strict JSON of a string, number, bool or null
+// node, data is the source data which the node is parsed from.
+func Literal(data []byte, n *Node) []byte {
+	switch n.Kind {
+	case NodeString:
+		if data[n.Begin] == '"' {
+			if end, ext := scanQuoted(data, n.Begin); end == n.End && ext < 0 {
+				return data[n.Begin:n.End]
+			}
+		}
+		return appendQuotedString(nil, n.Value)
+	case NodeNumber:
+		if n.Value != "" {
+			return []byte(n.Value)
+		}
+		return data[n.Begin:n.End]
+	case NodeBool:
+		if c := data[n.Begin]; c == 't' || c == 'T' {
+			return literalTrue
+		}
+		return literalFalse
+	}
+	return literalNull
+}
+
+var (
+	literalTrue  = []byte("true")
+	literalFalse = []byte("false")
+	literalNull  = []byte("null")
+)
+
+// AppendJSON appends the strict JSON of n to dst, data is the source
+// data which the node is parsed from.
+func AppendJSON(dst, data []byte, n *Node) []byte {
+	switch n.Kind {
+	case NodeObject:
+		dst = append(dst, '{')
+		for i, member := range n.Children {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = appendQuotedString(dst, member.Key)
+			dst = append(dst, ':')
+			dst = AppendJSON(dst, data, member)
+		}
+		return append(dst, '}')
+	case NodeArray:
+		dst = append(dst, '[')
+		for i, elem := range n.Children {
+			if i > 0 {
+				dst = append(dst, ',')
+			}
+			dst = AppendJSON(dst, data, elem)
+		}
+		return append(dst, ']')
+	}
+	return append(dst, Literal(data, n)...)
+}
@@ -1,13 +1,17 @@
 package extjson
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"os"
+	"path/filepath"
 
 	"github.com/jxskiss/extjson/internal/parser"
 )
 
-//go:generate peg -output ./internal/parser/json.peg.go json.peg
+//go:generate peg -output ./internal/parser/json.peg_test.go json.peg
 
 // Unmarshal parses the JSON-encoded data and stores the result in the
 // value pointed to by v.
@@ -15,20 +19,104 @@ import (
 // In addition to features of encoding/json, it enables extended features
 // such as "trailing comma", "comments", "file including", "refer", etc.
 // The extended features are documented in the README file.
+//
+// Values are stored following the rules of json.Unmarshal, the Offset
+// of a *json.UnmarshalTypeError is the offset of the value in data,
+// unless data contains directives.
 func Unmarshal(data []byte, v interface{}, options ...ExtOption) error {
+	return UnmarshalContext(context.Background(), data, v, options...)
+}
+
+// UnmarshalContext is like Unmarshal, but it accepts a context.
+//
+// The context is passed to functions which accept a context.Context as
+// the first argument, and it is checked before evaluating each directive,
+// thus parsing is aborted with the context's error after the context
+// is cancelled or its deadline is exceeded.
+func UnmarshalContext(ctx context.Context, data []byte, v interface{}, options ...ExtOption) error {
 	opt := new(extOptions).apply(options...)
-	includeRoot, err := opt.getIncludeRoot()
+	return opt.unmarshal(ctx, data, v)
+}
+
+func (o *extOptions) unmarshal(ctx context.Context, data []byte, v interface{}) error {
+	includeRoot, err := o.getIncludeRoot()
 	if err != nil {
 		return err
 	}
-	if err = opt.validateFuncs(); err != nil {
+	if err = o.validateFuncs(); err != nil {
 		return err
 	}
-	data, err = parser.Parse(data, includeRoot, opt.EnableEnv, opt.FuncMap)
-	if err != nil {
+	var compiled *schema
+	if o.Schema != nil {
+		if compiled, err = compileSchema(o.Schema, includeRoot); err != nil {
+			return err
+		}
+	}
+	popts := parser.Options{
+		Context:     ctx,
+		FilePath:    o.filePath,
+		IncludeRoot: includeRoot,
+		EnableEnv:   o.EnableEnv,
+		FuncMap:     o.FuncMap,
+		Disallowed:  o.Disallowed,
+		JSON5:       o.JSON5,
+		NonFinite:   o.NonFinite,
+		Hjson:       o.Hjson,
+		Python:      o.Python,
+		Bytes:       o.Bytes,
+		OnInclude:   o.onInclude,
+	}
+	if o.info != nil {
+		o.info.record(&popts)
+	}
+	var tree *parser.Tree
+	var sources *parser.SourceMap
+	if compiled != nil {
+		if tree, data, sources, err = parseTree(data, popts); err != nil {
+			return err
+		}
+		if err = compiled.validate(tree, data, sources); err != nil {
+			return err
+		}
+	}
+	if o.Unmarshaler != nil {
+		if tree != nil {
+			data = parser.AppendJSON(nil, data, tree.Root)
+		} else if !json.Valid(data) {
+			if data, err = parser.Parse(data, popts); err != nil {
+				return err
+			}
+		}
+		return o.Unmarshaler(data, v)
+	}
+
+	d := &decodeState{
+		useNumber:             o.UseNumber,
+		disallowUnknownFields: o.DisallowUnknownFields,
+		caseSensitive:         o.CaseSensitive,
+		applyDefaults:         o.ApplyDefaults,
+		validateTags:          o.ValidateTags,
+	}
+	if tree != nil {
+		d.data, d.sources = data, sources
+		return d.unmarshal(tree.Root, v)
+	}
+	// Without the options which json.Unmarshal does not support, the
+	// strict JSON is decoded by it, a json.RawMessage only needs it.
+	if _, isRaw := v.(*json.RawMessage); isRaw || !d.hasOptions() {
+		out, err := parser.Parse(data, popts)
+		if err != nil {
+			return err
+		}
+		err = json.Unmarshal(out, v)
+		if _, ok := err.(*json.UnmarshalTypeError); ok {
+			// The offset is in the strict JSON, decode again from the
+			// syntax tree to report the offset in the source.
+			return d.decode(data, v, popts)
+		}
 		return err
 	}
-	return json.Unmarshal(data, v)
+	return d.decode(data, v, popts)
 }
 
 // Clean parses data with extended feature and returns it as normal
@@ -42,6 +130,28 @@ func Clean(data []byte, options ...ExtOption) ([]byte, error) {
 	return raw, nil
 }
 
+// Valid reports whether data is valid extended JSON, it returns nil if
+// the data is valid, else an error describing the problem.
+//
+// Valid only checks the grammar and the features restricted by Allow
+// and EnableEnv, it does not rewrite the data or evaluate any directives,
+// thus errors such as missing included files or unresolvable references
+// are not reported.
+func Valid(data []byte, options ...ExtOption) error {
+	opt := new(extOptions).apply(options...)
+	return parser.Validate(data, parser.Options{
+		EnableEnv:  opt.EnableEnv,
+		Disallowed: opt.Disallowed,
+		JSON5:      opt.JSON5,
+		Hjson:      opt.Hjson,
+		Python:     opt.Python,
+	})
+}
+
+// RefCycleError is returned when references refer to each other in
+// a cycle, Path lists the referred paths, e.g. a.b -> c -> a.b.
+type RefCycleError = parser.RefCycleError
+
 // Load reads JSON-encoded data from the named file at path and stores
 // the result in the value pointed to by v.
 //
@@ -49,11 +159,19 @@ func Clean(data []byte, options ...ExtOption) ([]byte, error) {
 // such as "trailing comma", "comments", "file including", "refer" etc.
 // The extended features are documented in the README file.
 func Load(path string, v interface{}, options ...ExtOption) error {
+	return LoadContext(context.Background(), path, v, options...)
+}
+
+// LoadContext is like Load, but it accepts a context.
+// See UnmarshalContext for how the context is used.
+func LoadContext(ctx context.Context, path string, v interface{}, options ...ExtOption) error {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		return err
 	}
-	return Unmarshal(data, v, options...)
+	opt := new(extOptions).apply(options...)
+	opt.filePath = path
+	return opt.unmarshal(ctx, data, v)
 }
 
 // Dump writes v to the named file at path using JSON encoding.
@@ -61,15 +179,106 @@ func Load(path string, v interface{}, options ...ExtOption) error {
 // Optionally indent can be applied to the output, empty prefix and
 // indent disables indentation.
 // The output is friendly to read by humans.
-func Dump(path string, v interface{}, prefix, indent string) error {
-	file, err := os.Create(path)
+//
+// The data is written to a temporary file in the same directory, which
+// is then renamed to path, thus the file is never left truncated.
+// The mode of an existing file is preserved, a new file is created with
+// mode 0644.
+func Dump(path string, v interface{}, prefix, indent string, options ...DumpOption) (err error) {
+	opt := &dumpOptions{prefix: prefix, indent: indent}
+	opt.apply(options...)
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0644)
+	if fi, err := os.Stat(path); err == nil {
+		mode = fi.Mode().Perm()
+	}
+
+	dir, name := filepath.Split(path)
+	if dir == "" {
+		dir = "."
+	}
+	file, err := os.CreateTemp(dir, "."+name+".tmp*")
 	if err != nil {
 		return err
 	}
-	defer file.Close()
-	enc := json.NewEncoder(file)
-	enc.SetEscapeHTML(false)
-	enc.SetIndent(prefix, indent)
-	err = enc.Encode(v)
-	return err
+	tmpPath := file.Name()
+	defer func() {
+		if err != nil {
+			file.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+	if err = file.Chmod(mode); err != nil {
+		return err
+	}
+	if err = opt.encode(file, v); err != nil {
+		return err
+	}
+	if opt.sync {
+		if err = file.Sync(); err != nil {
+			return err
+		}
+	}
+	if err = file.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	if opt.sync {
+		syncDir(dir)
+	}
+	return nil
+}
+
+// syncDir flushes the directory entry of a renamed file to disk,
+// which is not supported on all platforms, thus errors are ignored.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// DumpTo writes v to w using JSON encoding.
+// By default, it disables HTMLEscape and writes without indentation.
+func DumpTo(w io.Writer, v interface{}, options ...DumpOption) error {
+	opt := new(dumpOptions)
+	opt.apply(options...)
+	return opt.encode(w, v)
+}
+
+func (o *dumpOptions) encode(w io.Writer, v interface{}) error {
+	if o.marshal != nil {
+		data, err := o.marshal(v)
+		if err != nil {
+			return err
+		}
+		// The output is encoded again as a raw message to apply the
+		// other options.
+		v = json.RawMessage(data)
+	}
+	if o.sortKeys {
+		// Round trip through generic values, which sorts all object
+		// keys, including struct fields.
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(o.escapeHTML)
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+		dec := json.NewDecoder(&buf)
+		dec.UseNumber()
+		var x interface{}
+		if err := dec.Decode(&x); err != nil {
+			return err
+		}
+		v = x
+	}
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(o.escapeHTML)
+	enc.SetIndent(o.prefix, o.indent)
+	return enc.Encode(v)
 }
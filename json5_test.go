@@ -0,0 +1,94 @@
+package extjson
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSON5TestSuite(t *testing.T) {
+	files, err := filepath.Glob("testdata/json5-tests/*/*")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("failed list test files: %v", err)
+	}
+	for _, file := range files {
+		ext := filepath.Ext(file)
+		if ext == ".md" {
+			continue
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("failed read test file: %v", err)
+		}
+		var got interface{}
+		err = Unmarshal(data, &got, EnableJSON5())
+		switch ext {
+		case ".json", ".json5":
+			if err != nil {
+				t.Errorf("%s: expecting valid, got error: %v", file, err)
+				continue
+			}
+			if ext == ".json" {
+				var want interface{}
+				_ = json.Unmarshal(data, &want)
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s: got %v, want %v", file, got, want)
+				}
+			}
+		case ".js", ".txt":
+			if err == nil {
+				t.Errorf("%s: expecting invalid, got %v", file, got)
+			}
+		}
+	}
+}
+
+func TestJSON5(t *testing.T) {
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{`[.5, 5., -.5, +5.e4, 0xC8, -0xc8, 0xc8e4, +0, -0.]`, `[0.5,5,-0.5,5e4,200,-200,51428,0,-0]`},
+		{`0x10000000000000000`, `18446744073709551616`},
+		{`[Infinity, -Infinity, +Infinity, NaN, -NaN]`, `[null,null,null,null,null]`},
+		{`'a\'b"c\x41é\0\v'`, `"a'b\"cAé\u0000\u000b"`},
+		{"'line 1 \\\nline 2'", `"line 1 line 2"`},
+		{`"😀 \a\c"`, `"😀 ac"`},
+		{`{ümlåût: 1, sigΣma: 2, $_a1: 3}`, `{"ümlåût":1,"sigΣma":2,"$_a1":3}`},
+		{"{\u00a0a\u2028: 1,\ufeff}", `{"a":1}`},
+		{"// comment at end\n[1] // no newline", `[1]`},
+	}
+	for _, tc := range testCases {
+		got, err := Clean([]byte(tc.data), EnableJSON5())
+		if err != nil {
+			t.Errorf("failed clean %s: %v", tc.data, err)
+			continue
+		}
+		if string(got) != tc.want {
+			t.Errorf("clean %s: got %s, want %s", tc.data, got, tc.want)
+		}
+	}
+
+	got, err := Clean([]byte(`[Infinity, -Infinity, NaN]`), EnableJSON5(), ConvertNonFinite(NonFiniteString))
+	if err != nil || string(got) != `["Infinity","-Infinity","NaN"]` {
+		t.Errorf("convert non-finite to string: got %s, %v", got, err)
+	}
+	_, err = Clean([]byte(`{a: [-Infinity]}`), EnableJSON5(), ConvertNonFinite(NonFiniteError))
+	if err == nil || err.Error() != "non-finite number -Infinity is not allowed at 1:7" {
+		t.Errorf("convert non-finite with error: got %v", err)
+	}
+
+	for _, data := range []string{`{a: .5}`, `[0x10]`, `"\x41"`, `{ümlåût: 1}`} {
+		_, err = Clean([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), "JSON5") || !strings.Contains(err.Error(), "mode is not enabled") {
+			t.Errorf("expecting JSON5 not enabled error for %s, got %v", data, err)
+		}
+	}
+	_, err = Clean([]byte(`{10twenty: 1}`), EnableJSON5())
+	if err == nil || err.Error() != `invalid identifier "10twenty" at 1:2` {
+		t.Errorf("expecting invalid identifier error, got %v", err)
+	}
+}
@@ -1,8 +1,14 @@
 package extjson
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -98,3 +104,234 @@ func TestUnmarshal_SingleQuote(t *testing.T) {
 		t.Errorf("unmarshal single quoted string: incorrect key value")
 	}
 }
+
+func TestUnmarshal_SingleQuoteEscapes(t *testing.T) {
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{`'\\'`, `"\\"`},
+		{`'\\\''`, `"\\'"`},
+		{`'\''`, `"'"`},
+		{`'\"'`, `"\""`},
+		{`'a"b'`, `"a\"b"`},
+		{`'\\"'`, `"\\\""`},
+		{`'\/\b\f\n\r\t\v'`, `"/\b\f\n\r\t\u000b"`},
+		{`'\x41\x7e'`, `"A~"`},
+		{`'\0'`, `"\u0000"`},
+		{`'\101\1012\7'`, `"AA2\u0007"`},
+		{`'\xe9é'`, `"éé"`},
+		{`'\U0001F600'`, `"😀"`},
+		{`'😀'`, `"😀"`},
+		{`'\ud83d'`, `"�"`},
+		{`'\ud83dx'`, `"�x"`},
+		{`'\\x41'`, `"\\x41"`},
+		{`{'\x6b': 'v'}`, `{"k":"v"}`},
+	}
+	for _, tc := range testCases {
+		got, err := Clean([]byte(tc.data))
+		if err != nil {
+			t.Errorf("failed clean %s: %v", tc.data, err)
+			continue
+		}
+		if string(got) != tc.want {
+			t.Errorf("clean %s: got = %s, want = %s", tc.data, got, tc.want)
+		}
+		if !json.Valid(got) {
+			t.Errorf("clean %s: invalid JSON %s", tc.data, got)
+		}
+	}
+
+	for _, data := range []string{`'\x4'`, `'\U0001F60'`, `'\U00110000'`, `'\q'`} {
+		if _, err := Clean([]byte(data)); err == nil {
+			t.Errorf("expecting error for %s", data)
+		}
+	}
+}
+
+func TestUnmarshalContext(t *testing.T) {
+	type ctxKey struct{}
+	funcs := FuncMap{
+		"fromCtx": func(ctx context.Context) string {
+			return ctx.Value(ctxKey{}).(string)
+		},
+		"both": func(ctx context.Context, fctx FuncContext, x int) int {
+			return x + fctx.Pos().Line
+		},
+	}
+	data := `{
+		a: @fn("fromCtx"),
+		b: @fn("both(10)"),
+	}`
+	ctx := context.WithValue(context.Background(), ctxKey{}, "ctx-value")
+	got := make(map[string]interface{})
+	err := UnmarshalContext(ctx, []byte(data), &got, WithFuncMap(funcs))
+	if err != nil {
+		t.Fatalf("failed unmarshal with context: %v", err)
+	}
+	if got["a"] != "ctx-value" || got["b"] != float64(13) {
+		t.Fatalf("got unexpected result: %v", got)
+	}
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = UnmarshalContext(cancelled, []byte(data), &got, WithFuncMap(funcs))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expecting context.Canceled, got %v", err)
+	}
+
+	err = LoadContext(cancelled, "testdata.json", &got)
+	if err != nil {
+		t.Fatalf("failed load without directives: %v", err)
+	}
+}
+
+func TestUnmarshal_WithUnmarshaler(t *testing.T) {
+	var inputs []string
+	unmarshal := func(data []byte, v interface{}) error {
+		inputs = append(inputs, string(data))
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		return dec.Decode(v)
+	}
+	type T struct {
+		A int
+		B int
+	}
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{`{"A": 1, "B": 2}`, `{"A": 1, "B": 2}`},
+		{`{A: 1, /* comment */ B: 2}`, `{"A":1,"B":2}`},
+		{`{A: 1, B: @ref("A")}`, `{"A":1,"B":1}`},
+	}
+	for _, tc := range testCases {
+		inputs = inputs[:0]
+		var got T
+		if err := Unmarshal([]byte(tc.data), &got, WithUnmarshaler(unmarshal)); err != nil {
+			t.Fatalf("failed unmarshal %s: %v", tc.data, err)
+		}
+		if len(inputs) != 1 || inputs[0] != tc.want || got.A != 1 {
+			t.Errorf("%s: got inputs %q, value %+v, want %q", tc.data, inputs, got, tc.want)
+		}
+	}
+
+	var got T
+	err := Unmarshal([]byte(`{A: 1, C: 3}`), &got, WithUnmarshaler(unmarshal))
+	if err == nil || !strings.Contains(err.Error(), "unknown field") {
+		t.Errorf("expecting error of unmarshal function, got %v", err)
+	}
+}
+
+func TestValid(t *testing.T) {
+	valid := []string{
+		`{"a": [1, 2, 3]}`,
+		malformedJSONData,
+		`{a: @incl("not-exist.json"), b: @ref("not.exist")}`,
+	}
+	for _, data := range valid {
+		if err := Valid([]byte(data), EnableEnv()); err != nil {
+			t.Errorf("expecting valid: %v\n%s", err, data)
+		}
+	}
+
+	invalid := []string{
+		`{"a": [1, 2, 3}`,
+		`{"a": @unknown("x")}`,
+		`{"a": 1 "b": 2}`,
+	}
+	for _, data := range invalid {
+		if err := Valid([]byte(data)); err == nil {
+			t.Errorf("expecting invalid: %s", data)
+		}
+	}
+
+	err := Valid([]byte(`{"a": @env("HOME")}`))
+	if err == nil || !strings.Contains(err.Error(), "@env directive is not allowed") {
+		t.Errorf("expecting env not allowed, got %v", err)
+	}
+	err = Valid([]byte(`{"a": 1, /* comment */}`), Allow(TrailingComma))
+	if err == nil || !strings.Contains(err.Error(), "comment is not allowed at 1:10") {
+		t.Errorf("expecting comment not allowed, got %v", err)
+	}
+}
+
+func TestUnmarshal_RefCycle(t *testing.T) {
+	dir := t.TempDir()
+	incPath := filepath.Join(dir, "inc.json")
+	err := os.WriteFile(incPath, []byte(`{a: {b: @ref("c")}, c: @ref("a.b")}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mainPath := filepath.Join(dir, "main.json")
+	err = os.WriteFile(mainPath, []byte(`{x: @ref("y"), y: [@ref("x")]}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got interface{}
+	err = Unmarshal([]byte(`{a: @ref("b"), b: @ref("a")}`), &got)
+	var cycle *RefCycleError
+	if !errors.As(err, &cycle) || !reflect.DeepEqual(cycle.Path, []string{"b", "a", "b"}) {
+		t.Errorf("direct cycle: got error %v", err)
+	}
+
+	err = Load(mainPath, &got)
+	if !errors.As(err, &cycle) || cycle.Error() != "circular reference y -> x -> y in "+mainPath {
+		t.Errorf("indirect cycle: got error %v", err)
+	}
+
+	err = Unmarshal([]byte(`{inc: @incl("inc.json"), d: @ref("inc.c")}`), &got, IncludeRoot(dir))
+	if !errors.As(err, &cycle) || cycle.File != incPath ||
+		!reflect.DeepEqual(cycle.Path, []string{"c", "a.b", "c"}) {
+		t.Errorf("cycle in included file: got error %v", err)
+	}
+}
+
+var benchStrictJSON = func() []byte {
+	var buf strings.Builder
+	buf.WriteString(`{"items": [`)
+	for i := 0; i < 1000; i++ {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString(`{"id": 12345, "name": "some name", "tags": ["a", "b", "c"], "ok": true, "score": 1.5e3}`)
+	}
+	buf.WriteString(`]}`)
+	return []byte(buf.String())
+}()
+
+func BenchmarkUnmarshal_StrictJSON_EncodingJSON(b *testing.B) {
+	b.SetBytes(int64(len(benchStrictJSON)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var raw json.RawMessage
+		if err := json.Unmarshal(benchStrictJSON, &raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal_StrictJSON(b *testing.B) {
+	b.SetBytes(int64(len(benchStrictJSON)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var raw json.RawMessage
+		if err := Unmarshal(benchStrictJSON, &raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshal_ExtendedJSON(b *testing.B) {
+	data := append([]byte("// comment\n"), benchStrictJSON...)
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var raw json.RawMessage
+		if err := Unmarshal(data, &raw); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -1,10 +1,15 @@
 package extjson
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"reflect"
+	"strings"
+	"time"
 	"unicode"
+
+	"github.com/jxskiss/extjson/internal/parser"
 )
 
 // EnableEnv enables reading environment variables.
@@ -16,6 +21,82 @@ func EnableEnv() ExtOption {
 		}}
 }
 
+// EnableJSON5 enables the JSON5 grammar (https://json5.org/) in addition
+// to the default extended features, e.g. hexadecimal numbers,
+// leading and trailing decimal points, explicit plus signs, Infinity and
+// NaN, escaped newlines in strings, and ECMAScript identifiers as object
+// keys. By default, Infinity and NaN are converted to null, which can be
+// changed by the option ConvertNonFinite.
+func EnableJSON5() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.JSON5 = true
+		}}
+}
+
+// EnableHjson enables the Hjson conveniences (https://hjson.github.io/)
+// in addition to the default extended features: quoteless strings which
+// end at the newline, optional commas between object members and array
+// elements, and multiline strings quoted by triple single quotes, with
+// the indentation stripped.
+func EnableHjson() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.Hjson = true
+		}}
+}
+
+// EnablePython enables Python literals as generated by repr() and
+// pprint, in addition to the default extended features: tuples and sets
+// which are converted to arrays, True, False and None as object keys,
+// number keys, nan and inf, string prefixes and Python escape sequences.
+// Bytes literals are converted to base64 encoded strings by default,
+// which can be changed by the option ConvertBytes.
+func EnablePython() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.Python = true
+		}}
+}
+
+// BytesEncoding specifies how to convert Python bytes literals.
+type BytesEncoding = parser.BytesEncoding
+
+// Options to convert Python bytes literals.
+const (
+	BytesBase64 = parser.BytesBase64 // convert to base64 encoded string
+	BytesText   = parser.BytesText   // convert to string, invalid UTF-8 is replaced by U+FFFD
+)
+
+// ConvertBytes specifies how to convert Python bytes literals,
+// the default is BytesBase64.
+func ConvertBytes(enc BytesEncoding) ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.Bytes = enc
+		}}
+}
+
+// NonFinite specifies how to convert non-finite numbers, i.e. Infinity
+// and NaN, which cannot be represented in strict JSON.
+type NonFinite = parser.NonFinite
+
+// Options to convert non-finite numbers.
+const (
+	NonFiniteNull   = parser.NonFiniteNull   // convert to null
+	NonFiniteString = parser.NonFiniteString // convert to string "Infinity", "-Infinity" or "NaN"
+	NonFiniteError  = parser.NonFiniteError  // report an error
+)
+
+// ConvertNonFinite specifies how to convert non-finite numbers,
+// the default is NonFiniteNull.
+func ConvertNonFinite(mode NonFinite) ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.NonFinite = mode
+		}}
+}
+
 // IncludeRoot specifies the root directory to use with the extended file
 // including feature.
 func IncludeRoot(dir string) ExtOption {
@@ -25,13 +106,65 @@ func IncludeRoot(dir string) ExtOption {
 		}}
 }
 
+// Feature is a bit mask of extended features, which can be combined by
+// the bitwise OR operator and passed to Allow.
+type Feature = parser.Feature
+
+// Extended features which can be restricted by Allow.
+const (
+	Comments      = parser.FeatureComments
+	TrailingComma = parser.FeatureTrailingComma
+	SingleQuote   = parser.FeatureSingleQuote
+	UnquotedKey   = parser.FeatureUnquotedKey
+	PyConstants   = parser.FeaturePyConstants
+	Env           = parser.FeatureEnv
+	Include       = parser.FeatureInclude
+	Ref           = parser.FeatureRef
+	Func          = parser.FeatureFunc
+
+	AllFeatures = parser.AllFeatures
+)
+
+// Allow restricts the extended features which can be used in the input
+// data, e.g. Allow(Comments|TrailingComma) accepts comments and trailing
+// commas but rejects any directive.
+// When a disallowed feature is found, an error naming the feature and
+// its position is returned.
+//
+// By default, all features are allowed, except that Env must also
+// be enabled by EnableEnv.
+// It is recommended to restrict the features when parsing untrusted data.
+func Allow(features Feature) ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.Disallowed = AllFeatures &^ features
+		},
+	}
+}
+
 // FuncMap is the type of the map defining the mapping from names to functions.
 // Each function must have either a single return value, or two return values of
 // which the second has type error. In that case, if the second (error)
 // return value evaluates to non-nil during execution, execution terminates and
 // the error will be returned.
+//
+// A function may optionally accept a context.Context as its first argument,
+// and/or a FuncContext which gives access to the document being parsed.
+// When both are accepted, the context.Context must come first.
 type FuncMap map[string]interface{}
 
+// FuncContext provides information about the document being parsed to
+// functions which are called by the "@fn" directive.
+// A function receives it by declaring a leading FuncContext parameter.
+type FuncContext = parser.FuncContext
+
+// Position describes a location in the input data.
+type Position = parser.Position
+
+// Source describes a location in a file, File is empty for data which
+// is not read from a file.
+type Source = parser.Source
+
 // WithFuncMap specifies additional functions to use with the "@fn" directive.
 func WithFuncMap(funcMap FuncMap) ExtOption {
 	return ExtOption{
@@ -41,6 +174,107 @@ func WithFuncMap(funcMap FuncMap) ExtOption {
 	}
 }
 
+// UseNumber causes numbers to be decoded into an interface{} as
+// json.Number instead of float64, like json.Decoder.UseNumber.
+func UseNumber() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.UseNumber = true
+		}}
+}
+
+// DisallowUnknownFields causes an *UnknownFieldError to be returned
+// when an object key does not match any field of the destination struct,
+// like json.Decoder.DisallowUnknownFields.
+func DisallowUnknownFields() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.DisallowUnknownFields = true
+		}}
+}
+
+// CaseSensitive causes object keys to match struct fields only if the
+// case is the same, by default keys are matched case-insensitively like
+// json.Unmarshal.
+func CaseSensitive() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.CaseSensitive = true
+		}}
+}
+
+// ApplyDefaults fills struct fields which are zero after decoding with
+// the values of their default tags, e.g. `default:"8080"`.
+// The value is the text of a string, a time.Duration or an
+// encoding.TextUnmarshaler, or else extended JSON, e.g. `default:"[1, 2]"`.
+func ApplyDefaults() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.ApplyDefaults = true
+		}}
+}
+
+// ValidateTags checks struct fields against the rules of their validate
+// tags after decoding and applying defaults, e.g.
+// `validate:"required,min=1,oneof=a b"`.
+// Every invalid field is reported by a *ValidationError, with the path
+// and the source location of the value.
+//
+// The rules are "required", which requires a non-zero value, "min=N",
+// "max=N" and "len=N", which check numbers, the length of strings in
+// characters and the length of slices, arrays and maps, and "oneof",
+// which checks strings and integers against values separated by spaces.
+// Rules other than "required" are not checked for nil pointers.
+func ValidateTags() ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.ValidateTags = true
+		}}
+}
+
+// WithSchema validates the document against the JSON Schema before it
+// is decoded, the directives are evaluated before validating.
+// Every violation is reported by a *SchemaError, with the path and the
+// source location of the value.
+//
+// A subset of JSON Schema draft 2020-12 is supported: the keywords type,
+// enum, required, pattern, minimum, maximum, exclusiveMinimum,
+// exclusiveMaximum, minLength, maxLength, minItems, maxItems,
+// minProperties, maxProperties, items, properties, additionalProperties,
+// anyOf, oneOf and $ref to the same schema, e.g. "#/$defs/port".
+// Other keywords are ignored. The schema may be written in extended JSON,
+// files included by "@incl" in the schema are resolved against the
+// include root, as the files included by the document.
+func WithSchema(schema []byte) ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.Schema = schema
+		},
+	}
+}
+
+// WithUnmarshaler specifies the function to store the data converted to
+// strict JSON in v, e.g. the Unmarshal function of another JSON library,
+// instead of json.Unmarshal.
+// The options UseNumber, DisallowUnknownFields, CaseSensitive,
+// ApplyDefaults and ValidateTags are not applied to the function.
+func WithUnmarshaler(unmarshal func(data []byte, v interface{}) error) ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.Unmarshaler = unmarshal
+		},
+	}
+}
+
+// WatchInterval specifies how often Watch checks whether the files have
+// changed, the default is one second.
+func WatchInterval(d time.Duration) ExtOption {
+	return ExtOption{
+		apply: func(options *extOptions) {
+			options.WatchInterval = d
+		}}
+}
+
 // ExtOption represents an option to customize the extended features.
 type ExtOption struct {
 	apply func(options *extOptions)
@@ -50,6 +284,26 @@ type extOptions struct {
 	EnableEnv   bool
 	IncludeRoot string
 	FuncMap     FuncMap
+	Disallowed  Feature
+	JSON5       bool
+	NonFinite   NonFinite
+	Hjson       bool
+	Python      bool
+	Bytes       BytesEncoding
+	Unmarshaler func([]byte, interface{}) error
+	Schema      []byte
+
+	UseNumber             bool
+	DisallowUnknownFields bool
+	CaseSensitive         bool
+	ApplyDefaults         bool
+	ValidateTags          bool
+
+	WatchInterval time.Duration
+
+	filePath  string
+	onInclude func(path string, data []byte)
+	info      *ParseInfo
 }
 
 func (o *extOptions) apply(opts ...ExtOption) *extOptions {
@@ -68,21 +322,44 @@ func (o *extOptions) getIncludeRoot() (string, error) {
 
 func (o *extOptions) validateFuncs() error {
 	for name, fn := range o.FuncMap {
-		if !goodName(name) {
-			return fmt.Errorf("function name %q is not a valid identifier", name)
+		if err := validateFunc(name, fn); err != nil {
+			return err
 		}
-		typ := reflect.TypeOf(fn)
-		if typ.Kind() != reflect.Func {
-			return fmt.Errorf("value for %q is not a function", name)
-		}
-		if !goodFunc(typ) {
-			return fmt.Errorf("function %q has invalid signature", name)
+	}
+	return nil
+}
+
+func validateFunc(name string, fn interface{}) error {
+	if !goodName(name) {
+		return fmt.Errorf("function name %q is not a valid identifier", name)
+	}
+	typ := reflect.TypeOf(fn)
+	if typ == nil || typ.Kind() != reflect.Func {
+		return fmt.Errorf("value for %q is not a function", name)
+	}
+	if !goodFunc(typ) {
+		return fmt.Errorf("function %q has invalid signature", name)
+	}
+	for i := 0; i < typ.NumIn(); i++ {
+		switch typ.In(i) {
+		case contextType:
+			if i != 0 {
+				return fmt.Errorf("function %q must accept context.Context as the first argument", name)
+			}
+		case funcContextType:
+			if i != 0 && (i != 1 || typ.In(0) != contextType) {
+				return fmt.Errorf("function %q must accept FuncContext as the first argument", name)
+			}
 		}
 	}
 	return nil
 }
 
-var errorType = reflect.TypeOf((*error)(nil)).Elem()
+var (
+	errorType       = reflect.TypeOf((*error)(nil)).Elem()
+	contextType     = reflect.TypeOf((*context.Context)(nil)).Elem()
+	funcContextType = reflect.TypeOf((*FuncContext)(nil)).Elem()
+)
 
 // goodFunc reports whether the function or method has the right result signature.
 func goodFunc(typ reflect.Type) bool {
@@ -96,8 +373,18 @@ func goodFunc(typ reflect.Type) bool {
 	return false
 }
 
-// goodName reports whether the function name is a valid identifier.
+// goodName reports whether the function name is a valid identifier,
+// or a sequence of identifiers separated by dots, e.g. "str.upper".
 func goodName(name string) bool {
+	for _, ident := range strings.Split(name, ".") {
+		if !goodIdent(ident) {
+			return false
+		}
+	}
+	return true
+}
+
+func goodIdent(name string) bool {
 	if name == "" {
 		return false
 	}
@@ -112,3 +399,70 @@ func goodName(name string) bool {
 	}
 	return true
 }
+
+// DumpOption configures Dump and DumpTo.
+type DumpOption struct {
+	apply func(options *dumpOptions)
+}
+
+type dumpOptions struct {
+	prefix     string
+	indent     string
+	sortKeys   bool
+	escapeHTML bool
+	sync       bool
+	marshal    func(interface{}) ([]byte, error)
+}
+
+func (o *dumpOptions) apply(opts ...DumpOption) *dumpOptions {
+	for _, opt := range opts {
+		opt.apply(o)
+	}
+	return o
+}
+
+// DumpIndent applies indentation to the output, like json.MarshalIndent.
+func DumpIndent(prefix, indent string) DumpOption {
+	return DumpOption{
+		apply: func(options *dumpOptions) {
+			options.prefix = prefix
+			options.indent = indent
+		}}
+}
+
+// DumpSortKeys sorts object keys in the output, including struct fields,
+// which are written in declaration order by default.
+func DumpSortKeys() DumpOption {
+	return DumpOption{
+		apply: func(options *dumpOptions) {
+			options.sortKeys = true
+		}}
+}
+
+// DumpEscapeHTML escapes the characters <, > and & in strings.
+func DumpEscapeHTML() DumpOption {
+	return DumpOption{
+		apply: func(options *dumpOptions) {
+			options.escapeHTML = true
+		}}
+}
+
+// DumpSync flushes the file to disk before it is renamed by Dump.
+// It is ignored by DumpTo.
+func DumpSync() DumpOption {
+	return DumpOption{
+		apply: func(options *dumpOptions) {
+			options.sync = true
+		}}
+}
+
+// DumpMarshaler specifies the function to encode v as JSON, e.g. the
+// Marshal function of another JSON library, instead of json.Marshal.
+// The other options are applied to its output, except that characters
+// which are already escaped are not unescaped.
+func DumpMarshaler(marshal func(v interface{}) ([]byte, error)) DumpOption {
+	return DumpOption{
+		apply: func(options *dumpOptions) {
+			options.marshal = marshal
+		}}
+}
@@ -17,3 +17,49 @@ func TestDisableEnv(t *testing.T) {
 		t.Fatalf("failed unmarshal extended json: %v", err)
 	}
 }
+
+func TestAllow(t *testing.T) {
+	data := `{
+	// comment
+	"a": [1, 2, ],
+	'b': True,
+	c: @ref("a.0"),
+}`
+	got := make(map[string]interface{})
+	err := Unmarshal([]byte(data), &got, Allow(Comments|TrailingComma|SingleQuote|UnquotedKey|PyConstants|Ref))
+	if err != nil {
+		t.Fatalf("failed unmarshal with allowed features: %v", err)
+	}
+
+	testCases := []struct {
+		allow Feature
+		err   string
+	}{
+		{AllFeatures &^ Comments, "comment is not allowed at 2:2"},
+		{AllFeatures &^ TrailingComma, "trailing comma is not allowed at 3:12"},
+		{AllFeatures &^ SingleQuote, "single quote string is not allowed at 4:2"},
+		{AllFeatures &^ PyConstants, "Python constant is not allowed at 4:7"},
+		{AllFeatures &^ UnquotedKey, "unquoted object key is not allowed at 5:2"},
+		{AllFeatures &^ Ref, "@ref directive is not allowed at 5:5"},
+	}
+	for _, tc := range testCases {
+		err = Unmarshal([]byte(data), &got, Allow(tc.allow))
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("allow %v: got error %v, want %q", tc.allow, err, tc.err)
+		}
+	}
+
+	err = Unmarshal([]byte(`{"a": @incl("testdata.json")}`), &got, Allow(Include))
+	if err == nil || !strings.Contains(err.Error(), "comment is not allowed at 2:3") {
+		t.Errorf("included file: got error %v", err)
+	}
+}
+
+func TestTrailingCommaAndUnquotedKey(t *testing.T) {
+	for _, data := range []string{`[1,]`, `[1, ]`, `{"a":1,}`, `{a: 1}`, `{"a" : 1,}`} {
+		var got interface{}
+		if err := Unmarshal([]byte(data), &got); err != nil {
+			t.Errorf("failed unmarshal %s: %v", data, err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package extjson
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPython(t *testing.T) {
+	testCases := []struct {
+		data string
+		want string
+	}{
+		{`{'a': (1, 2), 'b': (), 'c': (1,)}`, `{"a":[1,2],"b":[],"c":[1]}`},
+		{`{'s': {1, 2, 3}, 'e': set(), 'f': frozenset({'x'}), 'g': frozenset()}`, `{"s":[1,2,3],"e":[],"f":["x"],"g":[]}`},
+		{`{True: 1, False: 2, None: 3, 1: 'a', -2.5: 'b'}`, `{"true":1,"false":2,"null":3,"1":"a","-2.5":"b"}`},
+		{`[nan, inf, -inf]`, `[null,null,null]`},
+		{`[u'abc', b'abc', B"\x00\xff"]`, `["abc","YWJj","AP8="]`},
+		{`['\x41\101é\U0001F600', '\a\v\q']`, `["AAé😀","\u0007\u000b\\q"]`},
+		{`['it\'s', "say \"hi\""]`, `["it's","say \"hi\""]`},
+	}
+	for _, tc := range testCases {
+		got, err := Clean([]byte(tc.data), EnablePython())
+		if err != nil {
+			t.Errorf("failed clean %s: %v", tc.data, err)
+			continue
+		}
+		if string(got) != tc.want {
+			t.Errorf("clean %s: got = %s, want = %s", tc.data, got, tc.want)
+		}
+	}
+
+	got, err := Clean([]byte(`[b'caf\xc3\xa9', b'\xff']`), EnablePython(), ConvertBytes(BytesText))
+	if err != nil || string(got) != `["café","�"]` {
+		t.Errorf("convert bytes to text: got %s, %v", got, err)
+	}
+	_, err = Clean([]byte(`[b'abc', b'é']`), EnablePython())
+	if err == nil || err.Error() != "bytes can only contain ASCII characters at 1:11" {
+		t.Errorf("expecting non-ASCII bytes error, got %v", err)
+	}
+
+	for _, data := range []string{`(1, 2)`, `{1, 2}`, `set()`, `[b'abc']`, `{-1: 2}`, `[nan]`} {
+		_, err = Clean([]byte(data))
+		if err == nil || !strings.Contains(err.Error(), "Python mode is not enabled") {
+			t.Errorf("expecting Python not enabled error for %s, got %v", data, err)
+		}
+	}
+	if err = Valid([]byte(`{'a': (1, 2)}`), EnablePython()); err != nil {
+		t.Errorf("expecting valid Python literal, got %v", err)
+	}
+}
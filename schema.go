@@ -0,0 +1,637 @@
+package extjson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// SchemaError is returned when a document does not conform to the
+// schema specified by the option WithSchema, it lists every violation.
+type SchemaError struct {
+	Violations []SchemaViolation
+}
+
+func (e *SchemaError) Error() string {
+	if len(e.Violations) == 1 {
+		return "schema violation: " + e.Violations[0].String()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d schema violations:", len(e.Violations))
+	for _, v := range e.Violations {
+		b.WriteString("\n\t")
+		b.WriteString(v.String())
+	}
+	return b.String()
+}
+
+// SchemaViolation describes a value which does not conform to a schema.
+type SchemaViolation struct {
+	// Path is the path of the value, in the format used by Document,
+	// it is empty for the root value.
+	Path string
+
+	// Source is the location of the value, or of the key for a property
+	// which is not allowed. Values pulled in by "@incl" and "@ref" are
+	// located in the files they are written in.
+	Source Source
+
+	Message string
+}
+
+func (v SchemaViolation) String() string {
+	if v.Path == "" {
+		return fmt.Sprintf("%v: %s", v.Source, v.Message)
+	}
+	return fmt.Sprintf("%v: %s: %s", v.Source, v.Path, v.Message)
+}
+
+// schema is a compiled JSON Schema.
+type schema struct {
+	reject bool // the false schema
+
+	ref     *schema
+	refPath string
+
+	types   []string
+	enum    []interface{}
+	hasEnum bool
+
+	minimum, maximum                   *schemaNumber
+	exclusiveMinimum, exclusiveMaximum *schemaNumber
+
+	minLength, maxLength int
+	pattern              *regexp.Regexp
+
+	minItems, maxItems int
+	items              *schema
+
+	minProperties, maxProperties int
+	required                     []string
+	properties                   map[string]*schema
+	additionalProperties         *schema
+
+	anyOf []*schema
+	oneOf []*schema
+}
+
+type schemaNumber struct {
+	value float64
+	text  string
+}
+
+var schemaTypes = map[string]bool{
+	"null": true, "boolean": true, "object": true, "array": true,
+	"number": true, "string": true, "integer": true,
+}
+
+// compileSchema compiles a JSON Schema written in extended JSON,
+// included files are resolved against includeRoot.
+func compileSchema(data []byte, includeRoot string) (*schema, error) {
+	data, err := parser.Parse(data, parser.Options{IncludeRoot: includeRoot})
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var root interface{}
+	if err = dec.Decode(&root); err != nil {
+		return nil, err
+	}
+	c := &schemaCompiler{root: root, schemas: make(map[string]*schema)}
+	s, err := c.compile(root, "")
+	if err != nil {
+		return nil, err
+	}
+	// Resolving references may compile more schemas with references.
+	for i := 0; i < len(c.refs); i++ {
+		if err = c.resolve(c.refs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+type schemaCompiler struct {
+	root    interface{}
+	schemas map[string]*schema // by JSON pointer
+	refs    []*schema
+}
+
+func (c *schemaCompiler) compile(v interface{}, ptr string) (*schema, error) {
+	if s := c.schemas[ptr]; s != nil {
+		return s, nil
+	}
+	s := &schema{
+		minLength: -1, maxLength: -1,
+		minItems: -1, maxItems: -1,
+		minProperties: -1, maxProperties: -1,
+	}
+	c.schemas[ptr] = s
+	switch v := v.(type) {
+	case bool:
+		s.reject = !v
+		return s, nil
+	case map[string]interface{}:
+		if err := c.compileKeywords(s, v, ptr); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+	return nil, schemaCompileError(ptr, "schema must be an object or a boolean")
+}
+
+func (c *schemaCompiler) compileKeywords(s *schema, m map[string]interface{}, ptr string) (err error) {
+	// Keywords are compiled in sorted order, to report the same error
+	// for the same schema.
+	for _, key := range sortedKeys(m) {
+		v := m[key]
+		kptr := ptr + "/" + escapePointer(key)
+		switch key {
+		case "$ref":
+			ref, ok := v.(string)
+			if !ok {
+				return schemaCompileError(kptr, "must be a string")
+			}
+			s.refPath = ref
+			c.refs = append(c.refs, s)
+		case "type":
+			switch v := v.(type) {
+			case string:
+				s.types = []string{v}
+			case []interface{}:
+				for _, x := range v {
+					t, ok := x.(string)
+					if !ok {
+						return schemaCompileError(kptr, "must be a string or an array of strings")
+					}
+					s.types = append(s.types, t)
+				}
+			default:
+				return schemaCompileError(kptr, "must be a string or an array of strings")
+			}
+			for _, t := range s.types {
+				if !schemaTypes[t] {
+					return schemaCompileError(kptr, fmt.Sprintf("unknown type %q", t))
+				}
+			}
+		case "enum":
+			enum, ok := v.([]interface{})
+			if !ok {
+				return schemaCompileError(kptr, "must be an array")
+			}
+			s.enum, s.hasEnum = enum, true
+		case "minimum":
+			s.minimum, err = schemaNumberValue(v, kptr)
+		case "maximum":
+			s.maximum, err = schemaNumberValue(v, kptr)
+		case "exclusiveMinimum":
+			s.exclusiveMinimum, err = schemaNumberValue(v, kptr)
+		case "exclusiveMaximum":
+			s.exclusiveMaximum, err = schemaNumberValue(v, kptr)
+		case "minLength":
+			s.minLength, err = schemaCountValue(v, kptr)
+		case "maxLength":
+			s.maxLength, err = schemaCountValue(v, kptr)
+		case "minItems":
+			s.minItems, err = schemaCountValue(v, kptr)
+		case "maxItems":
+			s.maxItems, err = schemaCountValue(v, kptr)
+		case "minProperties":
+			s.minProperties, err = schemaCountValue(v, kptr)
+		case "maxProperties":
+			s.maxProperties, err = schemaCountValue(v, kptr)
+		case "pattern":
+			pattern, ok := v.(string)
+			if !ok {
+				return schemaCompileError(kptr, "must be a string")
+			}
+			if s.pattern, err = regexp.Compile(pattern); err != nil {
+				return schemaCompileError(kptr, err.Error())
+			}
+		case "required":
+			required, ok := v.([]interface{})
+			if !ok {
+				return schemaCompileError(kptr, "must be an array of strings")
+			}
+			for _, x := range required {
+				name, ok := x.(string)
+				if !ok {
+					return schemaCompileError(kptr, "must be an array of strings")
+				}
+				s.required = append(s.required, name)
+			}
+		case "properties":
+			properties, ok := v.(map[string]interface{})
+			if !ok {
+				return schemaCompileError(kptr, "must be an object")
+			}
+			s.properties = make(map[string]*schema, len(properties))
+			for _, name := range sortedKeys(properties) {
+				if s.properties[name], err = c.compile(properties[name], kptr+"/"+escapePointer(name)); err != nil {
+					return err
+				}
+			}
+		case "additionalProperties":
+			s.additionalProperties, err = c.compile(v, kptr)
+		case "items":
+			s.items, err = c.compile(v, kptr)
+		case "anyOf":
+			s.anyOf, err = c.compileList(v, kptr)
+		case "oneOf":
+			s.oneOf, err = c.compileList(v, kptr)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *schemaCompiler) compileList(v interface{}, ptr string) ([]*schema, error) {
+	list, ok := v.([]interface{})
+	if !ok || len(list) == 0 {
+		return nil, schemaCompileError(ptr, "must be a non-empty array")
+	}
+	schemas := make([]*schema, len(list))
+	for i, x := range list {
+		s, err := c.compile(x, ptr+"/"+strconv.Itoa(i))
+		if err != nil {
+			return nil, err
+		}
+		schemas[i] = s
+	}
+	return schemas, nil
+}
+
+// resolve resolves the reference of s, only references to JSON pointers
+// in the same schema are supported, e.g. "#/$defs/port".
+func (c *schemaCompiler) resolve(s *schema) error {
+	if !strings.HasPrefix(s.refPath, "#") {
+		return fmt.Errorf("invalid schema: unsupported $ref %q, only references in the same schema are supported", s.refPath)
+	}
+	ptr, err := url.PathUnescape(s.refPath[1:])
+	if err != nil || ptr != "" && ptr[0] != '/' {
+		return fmt.Errorf("invalid schema: invalid $ref %q", s.refPath)
+	}
+	v := c.root
+	if ptr != "" {
+		for _, token := range strings.Split(ptr[1:], "/") {
+			token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+			switch x := v.(type) {
+			case map[string]interface{}:
+				v = x[token]
+			case []interface{}:
+				i, err := strconv.Atoi(token)
+				if err != nil || i < 0 || i >= len(x) {
+					v = nil
+				} else {
+					v = x[i]
+				}
+			default:
+				v = nil
+			}
+			if v == nil {
+				return fmt.Errorf("invalid schema: cannot resolve $ref %q", s.refPath)
+			}
+		}
+	}
+	s.ref, err = c.compile(v, ptr)
+	return err
+}
+
+func schemaNumberValue(v interface{}, ptr string) (*schemaNumber, error) {
+	if n, ok := v.(json.Number); ok {
+		if f, err := n.Float64(); err == nil {
+			return &schemaNumber{value: f, text: n.String()}, nil
+		}
+	}
+	return nil, schemaCompileError(ptr, "must be a number")
+}
+
+func schemaCountValue(v interface{}, ptr string) (int, error) {
+	if n, ok := v.(json.Number); ok {
+		if i, err := n.Int64(); err == nil && i >= 0 && i <= math.MaxInt32 {
+			return int(i), nil
+		}
+	}
+	return 0, schemaCompileError(ptr, "must be a non-negative integer")
+}
+
+func schemaCompileError(ptr, msg string) error {
+	return fmt.Errorf("invalid schema: #%s: %s", ptr, msg)
+}
+
+func escapePointer(s string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(s)
+}
+
+// validate validates the syntax tree of a document against s,
+// it returns a *SchemaError listing every violation.
+func (s *schema) validate(tree *parser.Tree, data []byte, sources *parser.SourceMap) error {
+	v := &schemaValidator{data: data, sources: sources}
+	v.validate(s, tree.Root, "", 0)
+	if len(v.violations) > 0 {
+		return &SchemaError{Violations: v.violations}
+	}
+	return nil
+}
+
+// maxSchemaRefDepth limits the references which are followed for
+// the same value, to stop on references which refer to each other.
+const maxSchemaRefDepth = 100
+
+type schemaValidator struct {
+	data       []byte
+	sources    *parser.SourceMap
+	violations []SchemaViolation
+}
+
+func (v *schemaValidator) report(offset int, path, msg string) {
+	src, _ := v.sources.Lookup(offset)
+	v.violations = append(v.violations, SchemaViolation{Path: path, Source: src, Message: msg})
+}
+
+// matches tells whether n conforms to s, without reporting violations.
+func (v *schemaValidator) matches(s *schema, n *parser.Node, path string, refDepth int) bool {
+	mark := len(v.violations)
+	v.validate(s, n, path, refDepth)
+	ok := len(v.violations) == mark
+	v.violations = v.violations[:mark]
+	return ok
+}
+
+func (v *schemaValidator) validate(s *schema, n *parser.Node, path string, refDepth int) {
+	if s.reject {
+		v.report(n.Begin, path, "no value is allowed")
+		return
+	}
+	if s.ref != nil {
+		if refDepth >= maxSchemaRefDepth {
+			v.report(n.Begin, path, fmt.Sprintf("too many nested references at $ref %q", s.refPath))
+			return
+		}
+		v.validate(s.ref, n, path, refDepth+1)
+	}
+	if len(s.types) > 0 && !v.hasType(n, s.types) {
+		v.report(n.Begin, path, fmt.Sprintf("expected %s, got %s", strings.Join(s.types, " or "), nodeTypeName(n)))
+	}
+	if s.hasEnum && !v.inEnum(n, s.enum) {
+		v.report(n.Begin, path, "value must be one of "+formatEnum(s.enum))
+	}
+
+	switch n.Kind {
+	case parser.NodeNumber:
+		v.validateNumber(s, n, path)
+	case parser.NodeString:
+		length := utf8.RuneCountInString(n.Value)
+		if s.minLength >= 0 && length < s.minLength {
+			v.report(n.Begin, path, fmt.Sprintf("length must be >= %d, got %d", s.minLength, length))
+		}
+		if s.maxLength >= 0 && length > s.maxLength {
+			v.report(n.Begin, path, fmt.Sprintf("length must be <= %d, got %d", s.maxLength, length))
+		}
+		if s.pattern != nil && !s.pattern.MatchString(n.Value) {
+			v.report(n.Begin, path, fmt.Sprintf("string must match pattern %q", s.pattern))
+		}
+	case parser.NodeArray:
+		if s.minItems >= 0 && len(n.Children) < s.minItems {
+			v.report(n.Begin, path, fmt.Sprintf("must have at least %d items, got %d", s.minItems, len(n.Children)))
+		}
+		if s.maxItems >= 0 && len(n.Children) > s.maxItems {
+			v.report(n.Begin, path, fmt.Sprintf("must have at most %d items, got %d", s.maxItems, len(n.Children)))
+		}
+		if s.items != nil {
+			for i, elem := range n.Children {
+				v.validate(s.items, elem, appendPath(path, strconv.Itoa(i)), 0)
+			}
+		}
+	case parser.NodeObject:
+		v.validateObject(s, n, path)
+	}
+
+	if len(s.anyOf) > 0 {
+		matched := false
+		for _, sub := range s.anyOf {
+			if v.matches(sub, n, path, refDepth) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.report(n.Begin, path, "must match at least one schema of anyOf")
+		}
+	}
+	if len(s.oneOf) > 0 {
+		matched := 0
+		for _, sub := range s.oneOf {
+			if v.matches(sub, n, path, refDepth) {
+				matched++
+			}
+		}
+		if matched != 1 {
+			v.report(n.Begin, path, fmt.Sprintf("must match exactly one schema of oneOf, matched %d", matched))
+		}
+	}
+}
+
+func (v *schemaValidator) validateNumber(s *schema, n *parser.Node, path string) {
+	x, err := strconv.ParseFloat(string(parser.Literal(v.data, n)), 64)
+	if err != nil {
+		// Numbers out of the range of float64 are compared as infinity.
+		if numErr, ok := err.(*strconv.NumError); !ok || numErr.Err != strconv.ErrRange {
+			return
+		}
+	}
+	if s.minimum != nil && x < s.minimum.value {
+		v.report(n.Begin, path, "must be >= "+s.minimum.text)
+	}
+	if s.maximum != nil && x > s.maximum.value {
+		v.report(n.Begin, path, "must be <= "+s.maximum.text)
+	}
+	if s.exclusiveMinimum != nil && x <= s.exclusiveMinimum.value {
+		v.report(n.Begin, path, "must be > "+s.exclusiveMinimum.text)
+	}
+	if s.exclusiveMaximum != nil && x >= s.exclusiveMaximum.value {
+		v.report(n.Begin, path, "must be < "+s.exclusiveMaximum.text)
+	}
+}
+
+func (v *schemaValidator) validateObject(s *schema, n *parser.Node, path string) {
+	if s.minProperties >= 0 && len(n.Children) < s.minProperties {
+		v.report(n.Begin, path, fmt.Sprintf("must have at least %d properties, got %d", s.minProperties, len(n.Children)))
+	}
+	if s.maxProperties >= 0 && len(n.Children) > s.maxProperties {
+		v.report(n.Begin, path, fmt.Sprintf("must have at most %d properties, got %d", s.maxProperties, len(n.Children)))
+	}
+	for _, name := range s.required {
+		if findMember(n, name) == nil {
+			v.report(n.Begin, path, fmt.Sprintf("missing required property %q", name))
+		}
+	}
+	for _, member := range n.Children {
+		memberPath := appendPath(path, member.Key)
+		if sub, ok := s.properties[member.Key]; ok {
+			v.validate(sub, member, memberPath, 0)
+			continue
+		}
+		if s.additionalProperties == nil {
+			continue
+		}
+		if s.additionalProperties.reject {
+			v.report(member.KeyBegin, memberPath, fmt.Sprintf("property %q is not allowed", member.Key))
+			continue
+		}
+		v.validate(s.additionalProperties, member, memberPath, 0)
+	}
+}
+
+func findMember(n *parser.Node, key string) *parser.Node {
+	for _, member := range n.Children {
+		if member.Key == key {
+			return member
+		}
+	}
+	return nil
+}
+
+func (v *schemaValidator) hasType(n *parser.Node, types []string) bool {
+	for _, t := range types {
+		switch t {
+		case "null":
+			if n.Kind == parser.NodeNull {
+				return true
+			}
+		case "boolean":
+			if n.Kind == parser.NodeBool {
+				return true
+			}
+		case "object":
+			if n.Kind == parser.NodeObject {
+				return true
+			}
+		case "array":
+			if n.Kind == parser.NodeArray {
+				return true
+			}
+		case "string":
+			if n.Kind == parser.NodeString {
+				return true
+			}
+		case "number":
+			if n.Kind == parser.NodeNumber {
+				return true
+			}
+		case "integer":
+			if n.Kind == parser.NodeNumber && isIntegerNumber(string(parser.Literal(v.data, n))) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isIntegerNumber tells whether the JSON number s has no fractional
+// part, e.g. 1.0 and 1e3 are integers.
+func isIntegerNumber(s string) bool {
+	if !strings.ContainsAny(s, ".eE") {
+		return true
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return err == nil && f == math.Trunc(f)
+}
+
+func nodeTypeName(n *parser.Node) string {
+	switch n.Kind {
+	case parser.NodeObject:
+		return "object"
+	case parser.NodeArray:
+		return "array"
+	case parser.NodeString:
+		return "string"
+	case parser.NodeNumber:
+		return "number"
+	case parser.NodeBool:
+		return "boolean"
+	}
+	return "null"
+}
+
+func (v *schemaValidator) inEnum(n *parser.Node, enum []interface{}) bool {
+	for _, x := range enum {
+		if v.equal(n, x) {
+			return true
+		}
+	}
+	return false
+}
+
+// equal tells whether n is equal to the JSON value x, numbers are equal
+// if they have the same value, e.g. 1 and 1.0.
+func (v *schemaValidator) equal(n *parser.Node, x interface{}) bool {
+	switch x := x.(type) {
+	case nil:
+		return n.Kind == parser.NodeNull
+	case bool:
+		return n.Kind == parser.NodeBool && (string(parser.Literal(v.data, n)) == "true") == x
+	case string:
+		return n.Kind == parser.NodeString && n.Value == x
+	case json.Number:
+		if n.Kind != parser.NodeNumber {
+			return false
+		}
+		a, err1 := strconv.ParseFloat(string(parser.Literal(v.data, n)), 64)
+		b, err2 := x.Float64()
+		return err1 == nil && err2 == nil && a == b
+	case []interface{}:
+		if n.Kind != parser.NodeArray || len(n.Children) != len(x) {
+			return false
+		}
+		for i, elem := range n.Children {
+			if !v.equal(elem, x[i]) {
+				return false
+			}
+		}
+		return true
+	case map[string]interface{}:
+		if n.Kind != parser.NodeObject || len(n.Children) != len(x) {
+			return false
+		}
+		for _, member := range n.Children {
+			value, ok := x[member.Key]
+			if !ok || !v.equal(member, value) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func formatEnum(enum []interface{}) string {
+	values := make([]string, len(enum))
+	for i, x := range enum {
+		b, _ := json.Marshal(x)
+		values[i] = string(b)
+	}
+	return strings.Join(values, ", ")
+}
+
+// sortedKeys returns the keys of m in sorted order.
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,206 @@
+package extjson
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWithSchema(t *testing.T) {
+	schema := []byte(`{
+  // Extended JSON is allowed in the schema.
+  type: "object",
+  required: ["name", "servers"],
+  properties: {
+    name: {type: "string", minLength: 1},
+    servers: {type: "array", minItems: 1, items: {$ref: "#/$defs/server"}},
+    mode: {enum: ["dev", "prod"]},
+  },
+  additionalProperties: false,
+  $defs: {
+    server: {
+      type: "object",
+      required: ["host"],
+      properties: {
+        host: {type: "string", pattern: "^[a-z.]+$"},
+        port: {type: "integer", minimum: 1, maximum: 65535},
+      },
+    },
+  },
+}`)
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.json": `{
+  name: "",
+  servers: [
+    @incl("server.json"),
+    {host: "b", port: 1.5},
+    @ref("servers.0"),
+  ],
+  mode: "test",
+  debug: true,
+}`,
+		"server.json": "{\n  host: 'A',\n  port: 0,\n}",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mainPath := filepath.Join(dir, "main.json")
+	incPath := filepath.Join(dir, "server.json")
+
+	var v map[string]interface{}
+	err := Load(mainPath, &v, WithSchema(schema), IncludeRoot(dir))
+	schemaErr, ok := err.(*SchemaError)
+	if !ok {
+		t.Fatalf("expecting SchemaError, got %v", err)
+	}
+	want := []string{
+		mainPath + ":2:9: name: length must be >= 1, got 0",
+		incPath + ":2:9: servers.0.host: string must match pattern \"^[a-z.]+$\"",
+		incPath + ":3:9: servers.0.port: must be >= 1",
+		mainPath + ":5:23: servers.1.port: expected integer, got number",
+		incPath + ":2:9: servers.2.host: string must match pattern \"^[a-z.]+$\"",
+		incPath + ":3:9: servers.2.port: must be >= 1",
+		mainPath + ":8:9: mode: value must be one of \"dev\", \"prod\"",
+		mainPath + ":9:3: debug: property \"debug\" is not allowed",
+	}
+	var got []string
+	for _, x := range schemaErr.Violations {
+		got = append(got, x.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got violations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if v != nil {
+		t.Errorf("value should not be decoded, got %v", v)
+	}
+
+	err = Unmarshal([]byte(`{name: "x", servers: [{host: "a", port: 80}]}`), &v, WithSchema(schema))
+	if err != nil || v["name"] != "x" {
+		t.Errorf("got %v, %v", v, err)
+	}
+}
+
+func TestSchemaKeywords(t *testing.T) {
+	testCases := []struct {
+		schema string
+		data   string
+		want   []string
+	}{
+		{`true`, `{a: 1}`, nil},
+		{`false`, `1`, []string{"1:1: no value is allowed"}},
+		{`{type: ["string", "null"]}`, `null`, nil},
+		{`{type: "integer"}`, `1.0`, nil},
+		{`{type: "integer"}`, `1e3`, nil},
+		{`{type: "integer"}`, `"1"`, []string{"1:1: expected integer, got string"}},
+		{`{items: {enum: [1, [true], {a: null}]}}`, `[1.0, [true], {a: null}, {a: 1}]`,
+			[]string{`1:26: 3: value must be one of 1, [true], {"a":null}`}},
+		{`{exclusiveMinimum: 0, exclusiveMaximum: 10}`, `0`, []string{"1:1: must be > 0"}},
+		{`{exclusiveMinimum: 0, exclusiveMaximum: 10}`, `10`, []string{"1:1: must be < 10"}},
+		{`{maxLength: 2}`, `"日本語"`, []string{"1:1: length must be <= 2, got 3"}},
+		{`{maxLength: 3, pattern: "^日"}`, `"日本語"`, nil},
+		{`{maxItems: 1}`, `[1, 2]`, []string{"1:1: must have at most 1 items, got 2"}},
+		{`{minProperties: 1, maxProperties: 1}`, `{}`, []string{"1:1: must have at least 1 properties, got 0"}},
+		{`{properties: {"a.b": {type: "string"}}}`, `{"a.b": 1}`, []string{`1:9: a\.b: expected string, got number`}},
+		{`{additionalProperties: {type: "number"}, properties: {a: true}}`, `{a: "x", b: "y"}`,
+			[]string{"1:13: b: expected number, got string"}},
+		{`{anyOf: [{type: "string"}, {minimum: 1}]}`, `1`, nil},
+		{`{anyOf: [{type: "string"}, {minimum: 1}]}`, `0`, []string{"1:1: must match at least one schema of anyOf"}},
+		{`{oneOf: [{type: "integer"}, {minimum: 1}]}`, `0.5`, []string{"1:1: must match exactly one schema of oneOf, matched 0"}},
+		{`{oneOf: [{type: "integer"}, {minimum: 1}]}`, `2`, []string{"1:1: must match exactly one schema of oneOf, matched 2"}},
+		{`{oneOf: [{type: "integer"}, {minimum: 1}]}`, `1.5`, nil},
+		{`{$ref: "#/$defs/a~1b", $defs: {"a/b": {type: "array", items: {$ref: "#"}}}}`, `[[], [[1]]]`,
+			[]string{"1:8: 1.0.0: expected array, got number"}},
+		{`{$defs: {a: {$ref: "#/$defs/b"}, b: {$ref: "#/$defs/a"}}, $ref: "#/$defs/a"}`, `1`,
+			[]string{`1:1: too many nested references at $ref "#/$defs/a"`}},
+	}
+	for _, tc := range testCases {
+		var v interface{}
+		err := Unmarshal([]byte(tc.data), &v, WithSchema([]byte(tc.schema)))
+		var got []string
+		if err != nil {
+			schemaErr, ok := err.(*SchemaError)
+			if !ok {
+				t.Errorf("%s with %s: unexpected error %v", tc.data, tc.schema, err)
+				continue
+			}
+			for _, x := range schemaErr.Violations {
+				got = append(got, x.String())
+			}
+		}
+		if strings.Join(got, "\n") != strings.Join(tc.want, "\n") {
+			t.Errorf("%s with %s: got violations %q, want %q", tc.data, tc.schema, got, tc.want)
+		}
+	}
+}
+
+func TestSchemaError(t *testing.T) {
+	err := Unmarshal([]byte(`[1, "2"]`), new(interface{}), WithSchema([]byte(`{items: {type: "string"}}`)))
+	if err == nil || err.Error() != "schema violation: 1:2: 0: expected string, got number" {
+		t.Errorf("got error %v", err)
+	}
+	err = Unmarshal([]byte(`[1, 2]`), new(interface{}), WithSchema([]byte(`{items: {type: "string"}}`)))
+	want := "2 schema violations:\n\t1:2: 0: expected string, got number\n\t1:5: 1: expected string, got number"
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v", err)
+	}
+
+	invalidSchemas := map[string]string{
+		`{type: "str"}`:                     `invalid schema: #/type: unknown type "str"`,
+		`{properties: {a: {minimum: "1"}}}`: `invalid schema: #/properties/a/minimum: must be a number`,
+		`{items: [true]}`:                   `invalid schema: #/items: schema must be an object or a boolean`,
+		`{minItems: -1}`:                    `invalid schema: #/minItems: must be a non-negative integer`,
+		`{pattern: "("}`:                    "invalid schema: #/pattern: error parsing regexp: missing closing ): `(`",
+		`{$ref: "other.json"}`:              `invalid schema: unsupported $ref "other.json", only references in the same schema are supported`,
+		`{$ref: "#/$defs/x"}`:               `invalid schema: cannot resolve $ref "#/$defs/x"`,
+		`{anyOf: []}`:                       `invalid schema: #/anyOf: must be a non-empty array`,
+	}
+	for schema, want := range invalidSchemas {
+		err := Unmarshal([]byte(`{}`), new(interface{}), WithSchema([]byte(schema)))
+		if err == nil || err.Error() != want {
+			t.Errorf("%s: got error %v, want %v", schema, err, want)
+		}
+	}
+}
+
+func TestWithSchema_Unmarshaler(t *testing.T) {
+	var got string
+	unmarshal := func(data []byte, v interface{}) error {
+		got = string(data)
+		return nil
+	}
+	err := Unmarshal([]byte(`{a: 1, b: @ref("a")}`), new(interface{}),
+		WithSchema([]byte(`{properties: {b: {type: "integer"}}}`)), WithUnmarshaler(unmarshal))
+	if err != nil || got != `{"a":1,"b":1}` {
+		t.Errorf("got %s, %v", got, err)
+	}
+}
+
+func TestWithSchema_Include(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "port.schema.json"), []byte(`{type: "integer", maximum: 65535}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schema := []byte(`{properties: {port: @incl("port.schema.json")}}`)
+
+	// The schema is placed before IncludeRoot, which applies regardless.
+	err = Unmarshal([]byte(`{port: 80}`), new(interface{}), WithSchema(schema), IncludeRoot(dir))
+	if err != nil {
+		t.Fatalf("failed unmarshal with included schema: %v", err)
+	}
+	err = Unmarshal([]byte(`{port: 65536}`), new(interface{}), WithSchema(schema), IncludeRoot(dir))
+	if err == nil || !strings.Contains(err.Error(), "port: must be <= 65535") {
+		t.Errorf("expecting schema violation, got %v", err)
+	}
+
+	// Without IncludeRoot, the file is resolved against the working directory.
+	err = Unmarshal([]byte(`{port: 80}`), new(interface{}), WithSchema(schema))
+	if err == nil || !strings.Contains(err.Error(), "port.schema.json") {
+		t.Errorf("expecting error of missing included file, got %v", err)
+	}
+}
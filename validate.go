@@ -0,0 +1,314 @@
+package extjson
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jxskiss/extjson/internal/parser"
+)
+
+// ValidationError is returned when struct fields do not satisfy their
+// validate tags, if the option ValidateTags is used, it lists every
+// invalid field.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Fields) == 1 {
+		return "invalid field: " + e.Fields[0].String()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d invalid fields:", len(e.Fields))
+	for _, f := range e.Fields {
+		b.WriteString("\n\t")
+		b.WriteString(f.String())
+	}
+	return b.String()
+}
+
+// FieldError describes a struct field which does not satisfy a rule of
+// its validate tag.
+type FieldError struct {
+	// Path is the path of the field, in the format used by Document.
+	Path string
+
+	// Source is the location of the value, or of the object which does
+	// not contain the field.
+	Source Source
+
+	// Rule is the rule which is not satisfied, e.g. "min=1".
+	Rule string
+
+	Message string
+}
+
+func (e FieldError) String() string {
+	return fmt.Sprintf("%v: %s: %s", e.Source, e.Path, e.Message)
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// checkFields fills zero struct fields with the values of their default
+// tags and checks the rules of their validate tags, after v is decoded
+// from n.
+func (d *decodeState) checkFields(n *parser.Node, v reflect.Value) error {
+	var fieldErrs []FieldError
+	if err := d.checkValue(v, n, n, "", &fieldErrs); err != nil {
+		return err
+	}
+	if len(fieldErrs) > 0 {
+		return &ValidationError{Fields: fieldErrs}
+	}
+	return nil
+}
+
+// checkValue checks the struct fields in v, n is the node which v is
+// decoded from, it is nil if the value is not in the document, then
+// at is the node of the nearest enclosing value.
+func (d *decodeState) checkValue(v reflect.Value, n, at *parser.Node, path string, fieldErrs *[]FieldError) error {
+	if n != nil {
+		at = n
+	}
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		return d.checkValue(v.Elem(), n, at, path, fieldErrs)
+	case reflect.Struct:
+		return d.checkStruct(v, n, at, path, fieldErrs)
+	case reflect.Slice, reflect.Array:
+		if !mayHaveFields(v.Type().Elem()) {
+			return nil
+		}
+		for i := 0; i < v.Len(); i++ {
+			var elem *parser.Node
+			if n != nil && n.Kind == parser.NodeArray && i < len(n.Children) {
+				elem = n.Children[i]
+			}
+			if err := d.checkValue(v.Index(i), elem, at, appendPath(path, strconv.Itoa(i)), fieldErrs); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if !mayHaveFields(v.Type().Elem()) {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := mapKey(iter.Key())
+			if err != nil {
+				return err
+			}
+			var member *parser.Node
+			if n != nil && n.Kind == parser.NodeObject {
+				member = findMember(n, key)
+			}
+			// Map elements are not addressable, they are checked on
+			// a copy which is written back.
+			elem := reflect.New(v.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			if err := d.checkValue(elem, member, at, appendPath(path, key), fieldErrs); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	}
+	return nil
+}
+
+// mayHaveFields tells whether values of type t may contain structs.
+func mayHaveFields(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Struct, reflect.Slice, reflect.Array, reflect.Map:
+		return true
+	}
+	return false
+}
+
+func (d *decodeState) checkStruct(v reflect.Value, n, at *parser.Node, path string, fieldErrs *[]FieldError) error {
+	t := v.Type()
+	fields := cachedDecodeFields(t)
+	var members map[*decodeField]*parser.Node
+	if n != nil && n.Kind == parser.NodeObject {
+		members = make(map[*decodeField]*parser.Node, len(n.Children))
+		for _, member := range n.Children {
+			if f := fields.lookup(member.Key, d.caseSensitive); f != nil {
+				members[f] = member
+			}
+		}
+	}
+	for i := range fields.list {
+		f := &fields.list[i]
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		sf := t.FieldByIndex(f.index)
+		member := members[f]
+		fieldPath := appendPath(path, f.name)
+		if d.applyDefaults {
+			if value, ok := sf.Tag.Lookup("default"); ok && fv.IsZero() {
+				if err := setDefault(fv, value); err != nil {
+					return fmt.Errorf("invalid default tag %q of %v.%s: %v", value, t, sf.Name, err)
+				}
+			}
+		}
+		if d.validateTags {
+			if tag := sf.Tag.Get("validate"); tag != "" {
+				src := member
+				if src == nil {
+					src = at
+				}
+				for _, rule := range strings.Split(tag, ",") {
+					msg, err := checkRule(fv, rule)
+					if err != nil {
+						return fmt.Errorf("invalid validate tag %q of %v.%s: %v", tag, t, sf.Name, err)
+					}
+					if msg != "" {
+						source, _ := d.sources.Lookup(src.Begin)
+						*fieldErrs = append(*fieldErrs, FieldError{Path: fieldPath, Source: source, Rule: rule, Message: msg})
+					}
+				}
+			}
+		}
+		if err := d.checkValue(fv, member, at, fieldPath, fieldErrs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setDefault sets v to the value of a default tag, which is the text of
+// a string, a time.Duration or an encoding.TextUnmarshaler, or else
+// extended JSON.
+func setDefault(v reflect.Value, value string) error {
+	if v.Kind() == reflect.Ptr {
+		v.Set(reflect.New(v.Type().Elem()))
+		v = v.Elem()
+	}
+	switch {
+	case v.Type() == durationType:
+		x, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(x))
+		return nil
+	case reflect.PtrTo(v.Type()).Implements(textUnmarshalerType):
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(value))
+	case v.Kind() == reflect.String:
+		v.SetString(value)
+		return nil
+	}
+	return Unmarshal([]byte(value), v.Addr().Interface())
+}
+
+// checkRule checks whether v satisfies the rule, it returns a message
+// describing the problem if not, or an error if the rule is invalid.
+//
+// The rules are "required", which requires v to be non-zero, "min=N",
+// "max=N" and "len=N", which check numbers, the length of strings in
+// characters and the length of slices, arrays and maps, and "oneof=a b",
+// which checks strings and numbers.
+func checkRule(v reflect.Value, rule string) (string, error) {
+	name, param := rule, ""
+	if i := strings.IndexByte(rule, '='); i >= 0 {
+		name, param = rule[:i], rule[i+1:]
+	}
+	switch name {
+	case "required":
+		if param != "" {
+			return "", fmt.Errorf("rule %q has no parameter", name)
+		}
+		if v.IsZero() {
+			return "is required", nil
+		}
+		return "", nil
+	case "min", "max", "len", "oneof":
+	default:
+		return "", fmt.Errorf("unknown rule %q", name)
+	}
+	// Rules other than required are not checked for nil pointers.
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	if name == "oneof" {
+		return checkOneOf(v, param)
+	}
+	return checkBound(v, name, param)
+}
+
+func checkBound(v reflect.Value, name, param string) (string, error) {
+	bound, err := strconv.ParseFloat(param, 64)
+	if err != nil {
+		return "", fmt.Errorf("rule %q requires a number", name)
+	}
+	var x float64
+	what := ""
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		x = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		x = v.Float()
+	case reflect.String:
+		x, what = float64(utf8.RuneCountInString(v.String())), "length "
+	case reflect.Slice, reflect.Array, reflect.Map:
+		x, what = float64(v.Len()), "length "
+	default:
+		return "", fmt.Errorf("rule %q cannot be used with %v", name, v.Type())
+	}
+	switch {
+	case name == "min" && x < bound:
+		return what + "must be >= " + param, nil
+	case name == "max" && x > bound:
+		return what + "must be <= " + param, nil
+	case name == "len" && x != bound:
+		return what + "must be " + param, nil
+	}
+	return "", nil
+}
+
+func checkOneOf(v reflect.Value, param string) (string, error) {
+	var s string
+	quote := false
+	switch v.Kind() {
+	case reflect.String:
+		s, quote = v.String(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		s = strconv.FormatInt(v.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		s = strconv.FormatUint(v.Uint(), 10)
+	default:
+		return "", fmt.Errorf("rule \"oneof\" cannot be used with %v", v.Type())
+	}
+	values := strings.Fields(param)
+	if len(values) == 0 {
+		return "", fmt.Errorf("rule \"oneof\" requires values")
+	}
+	for _, x := range values {
+		if x == s {
+			return "", nil
+		}
+	}
+	if quote {
+		for i, x := range values {
+			values[i] = strconv.Quote(x)
+		}
+	}
+	return "must be one of " + strings.Join(values, ", "), nil
+}
@@ -0,0 +1,200 @@
+package extjson
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type validateServer struct {
+	Host string `json:"host" validate:"required"`
+	Port int    `json:"port" default:"80" validate:"min=1,max=65535"`
+}
+
+type validateEmbedded struct {
+	Region string `json:"region" default:"local" validate:"oneof=local remote"`
+}
+
+type validateConfig struct {
+	validateEmbedded
+	Name     string                    `json:"name" validate:"required,max=8"`
+	Mode     string                    `json:"mode" default:"dev" validate:"oneof=dev prod"`
+	Level    int                       `json:"level" validate:"oneof=1 2 3"`
+	Timeout  time.Duration             `json:"timeout" default:"1.5s"`
+	IP       net.IP                    `json:"ip" default:"127.0.0.1"`
+	Tags     []string                  `json:"tags" default:"['a', 'b']" validate:"len=2"`
+	Retries  *int                      `json:"retries" default:"3" validate:"max=5"`
+	Limit    *int                      `json:"limit" validate:"min=1"`
+	Servers  []validateServer          `json:"servers" validate:"min=1"`
+	Backends map[string]validateServer `json:"backends"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	data := `{
+  name: "x",
+  level: 1,
+  mode: "",
+  servers: [{host: "a"}, {host: "b", port: 8080}],
+  backends: {b: {host: "c"}},
+}`
+	var got validateConfig
+	if err := Unmarshal([]byte(data), &got, ApplyDefaults()); err != nil {
+		t.Fatal(err)
+	}
+	retries := 3
+	want := validateConfig{
+		validateEmbedded: validateEmbedded{Region: "local"},
+		Name:             "x",
+		Mode:             "dev",
+		Level:            1,
+		Timeout:          1500 * time.Millisecond,
+		IP:               net.ParseIP("127.0.0.1"),
+		Tags:             []string{"a", "b"},
+		Retries:          &retries,
+		Servers:          []validateServer{{Host: "a", Port: 80}, {Host: "b", Port: 8080}},
+		Backends:         map[string]validateServer{"b": {Host: "c", Port: 80}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v\nwant %+v", got, want)
+	}
+
+	// Defaults are not applied without the option.
+	got = validateConfig{}
+	if err := Unmarshal([]byte(data), &got); err != nil || got.Timeout != 0 || got.Servers[0].Port != 0 {
+		t.Errorf("got %+v, %v", got, err)
+	}
+}
+
+func TestValidateTags(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"main.json": `{
+  name: "too long name",
+  region: "moon",
+  level: 4,
+  retries: 10,
+  limit: 0,
+  servers: [
+    @incl("server.json"),
+    {port: 70000},
+  ],
+  backends: {b: @ref("servers.1")},
+}`,
+		"server.json": "{\n  host: '',\n  port: 0,\n}",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mainPath := filepath.Join(dir, "main.json")
+	incPath := filepath.Join(dir, "server.json")
+
+	var v validateConfig
+	err := Load(mainPath, &v, ApplyDefaults(), ValidateTags(), IncludeRoot(dir))
+	validationErr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("expecting ValidationError, got %v", err)
+	}
+	want := []string{
+		mainPath + ":3:11: region: must be one of \"local\", \"remote\"",
+		mainPath + ":2:9: name: length must be <= 8",
+		mainPath + ":4:10: level: must be one of 1, 2, 3",
+		mainPath + ":5:12: retries: must be <= 5",
+		mainPath + ":6:10: limit: must be >= 1",
+		incPath + ":2:9: servers.0.host: is required",
+		mainPath + ":9:5: servers.1.host: is required",
+		mainPath + ":9:12: servers.1.port: must be <= 65535",
+		mainPath + ":9:5: backends.b.host: is required",
+		mainPath + ":9:12: backends.b.port: must be <= 65535",
+	}
+	var got []string
+	for _, f := range validationErr.Fields {
+		got = append(got, f.String())
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if f := validationErr.Fields[0]; f.Rule != "oneof=local remote" || f.Path != "region" {
+		t.Errorf("got field error %+v", f)
+	}
+
+	// The default port is applied before validation.
+	v = validateConfig{}
+	err = Unmarshal([]byte(`{name: "x", level: 2, servers: [{host: "a"}]}`), &v, ApplyDefaults(), ValidateTags())
+	if err != nil || v.Servers[0].Port != 80 {
+		t.Errorf("got %+v, %v", v, err)
+	}
+	v = validateConfig{}
+	err = Unmarshal([]byte(`{name: "x", level: 2, servers: [{host: "a"}]}`), &v, ValidateTags())
+	wantErr := `4 invalid fields:
+	1:1: region: must be one of "local", "remote"
+	1:1: mode: must be one of "dev", "prod"
+	1:1: tags: length must be 2
+	1:33: servers.0.port: must be >= 1`
+	if err == nil || err.Error() != wantErr {
+		t.Errorf("got error %v", err)
+	}
+	err = Unmarshal([]byte(`{name: "", level: 2, servers: [{host: "a"}]}`), &v, ApplyDefaults(), ValidateTags())
+	if err == nil || err.Error() != "invalid field: 1:8: name: is required" {
+		t.Errorf("got error %v", err)
+	}
+}
+
+// validateKey is a map key which is different from its JSON key.
+type validateKey struct{ name string }
+
+func (k validateKey) MarshalText() ([]byte, error) { return []byte(k.name), nil }
+
+func (k *validateKey) UnmarshalText(text []byte) error {
+	k.name = string(text)
+	return nil
+}
+
+func TestValidateTags_MapKeys(t *testing.T) {
+	var v struct {
+		ByName map[validateKey]validateServer `json:"by_name"`
+		ByID   map[int8]validateServer        `json:"by_id"`
+	}
+	data := `{
+  by_name: {a: {host: "a", port: 0}},
+  by_id: {"-1": {host: "b", port: 0}},
+}`
+	err := Unmarshal([]byte(data), &v, ValidateTags())
+	want := `2 invalid fields:
+	2:34: by_name.a.port: must be >= 1
+	3:35: by_id.-1.port: must be >= 1`
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v", err)
+	}
+}
+
+func TestValidateTags_Invalid(t *testing.T) {
+	var v1 struct {
+		A int `validate:"between=1"`
+	}
+	var v2 struct {
+		A bool `validate:"min=1"`
+	}
+	var v3 struct {
+		A int `default:"x"`
+	}
+	testCases := []struct {
+		v    interface{}
+		want string
+	}{
+		{&v1, `invalid validate tag "between=1" of struct { A int "validate:\"between=1\"" }.A: unknown rule "between"`},
+		{&v2, `invalid validate tag "min=1" of struct { A bool "validate:\"min=1\"" }.A: rule "min" cannot be used with bool`},
+		{&v3, `invalid default tag "x" of struct { A int "default:\"x\"" }.A: `},
+	}
+	for _, tc := range testCases {
+		err := Unmarshal([]byte(`{}`), tc.v, ApplyDefaults(), ValidateTags())
+		if err == nil || !strings.HasPrefix(err.Error(), tc.want) {
+			t.Errorf("got error %v, want %v", err, tc.want)
+		}
+	}
+}
@@ -0,0 +1,210 @@
+package extjson
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultWatchInterval = time.Second
+
+// Watcher reloads a file when it or any file it includes changes,
+// see Watch.
+type Watcher struct {
+	path     string
+	typ      reflect.Type
+	options  []ExtOption
+	interval time.Duration
+
+	value atomic.Value // pointer to the current value
+
+	mu  sync.Mutex
+	err error
+
+	// files maps the paths of the watched files to the hashes of their
+	// content, it is only accessed by the polling goroutine after the
+	// first load.
+	files map[string]fileHash
+
+	changes chan struct{}
+	errors  chan error
+
+	closeOnce sync.Once
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// fileHash is the hash of the content of a file, it is zero for a file
+// which cannot be read.
+type fileHash [sha256.Size]byte
+
+// Watch loads the named file at path into the value pointed to by v like
+// Load, then it polls the file and the files included by "@incl", and
+// reloads them when any of them changes. Changes are checked by content,
+// at the interval specified by the option WatchInterval.
+//
+// Each reload decodes into a new value of the same type as v, which
+// replaces the current value returned by Current. The values are never
+// modified after they are loaded, thus they can be used concurrently.
+// If a reload fails, e.g. the file is being edited and has a syntax
+// error, the current value is kept and the error is reported by Err and
+// Errors, then the file is reloaded on the next change.
+//
+// An error is returned if the first load fails.
+// Close must be called to stop watching.
+func Watch(path string, v interface{}, options ...ExtOption) (*Watcher, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return nil, &json.InvalidUnmarshalError{Type: reflect.TypeOf(v)}
+	}
+	w := &Watcher{
+		path:     path,
+		typ:      rv.Type().Elem(),
+		options:  options,
+		interval: new(extOptions).apply(options...).WatchInterval,
+		changes:  make(chan struct{}, 1),
+		errors:   make(chan error, 1),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if w.interval <= 0 {
+		w.interval = defaultWatchInterval
+	}
+	files, err := w.load(v)
+	if err != nil {
+		return nil, err
+	}
+	w.files = files
+	w.value.Store(v)
+	go w.run()
+	return w, nil
+}
+
+// Current returns the current value, which is a pointer of the same
+// type as the value passed to Watch.
+func (w *Watcher) Current() interface{} {
+	return w.value.Load()
+}
+
+// Changes returns a channel which receives after the value is replaced
+// by a reload. Changes are coalesced if they are not received in time.
+func (w *Watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Errors returns a channel which receives the errors of failed reloads.
+// Only the latest error is kept if errors are not received in time.
+func (w *Watcher) Errors() <-chan error {
+	return w.errors
+}
+
+// Err returns the error of the last reload, it is nil if the last reload
+// succeeded or there has been no reload.
+func (w *Watcher) Err() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.err
+}
+
+// Close stops watching the files, the current value is still available.
+func (w *Watcher) Close() error {
+	w.closeOnce.Do(func() {
+		close(w.stop)
+	})
+	<-w.done
+	return nil
+}
+
+// load loads the file into v, it returns the hashes of the file and the
+// files which are included, including those failed to be read.
+func (w *Watcher) load(v interface{}) (map[string]fileHash, error) {
+	files := make(map[string]fileHash)
+	data, err := os.ReadFile(w.path)
+	files[w.path] = hashFile(data, err)
+	if err != nil {
+		return files, err
+	}
+	opt := new(extOptions).apply(w.options...)
+	opt.filePath = w.path
+	opt.onInclude = func(path string, data []byte) {
+		files[path] = hashFile(data, nil)
+	}
+	return files, opt.unmarshal(context.Background(), data, v)
+}
+
+func (w *Watcher) run() {
+	defer close(w.done)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			if w.changed() {
+				w.reload()
+			}
+		}
+	}
+}
+
+func (w *Watcher) changed() bool {
+	for path, hash := range w.files {
+		if readFileHash(path) != hash {
+			return true
+		}
+	}
+	return false
+}
+
+func (w *Watcher) reload() {
+	v := reflect.New(w.typ).Interface()
+	files, err := w.load(v)
+	if err != nil {
+		// Keep watching the files of the current value, which may be
+		// changed to fix the error.
+		for path := range w.files {
+			if _, ok := files[path]; !ok {
+				files[path] = readFileHash(path)
+			}
+		}
+		w.files = files
+		w.setErr(err)
+		select {
+		case <-w.errors:
+		default:
+		}
+		w.errors <- err
+		return
+	}
+	w.files = files
+	w.value.Store(v)
+	w.setErr(nil)
+	select {
+	case w.changes <- struct{}{}:
+	default:
+	}
+}
+
+func (w *Watcher) setErr(err error) {
+	w.mu.Lock()
+	w.err = err
+	w.mu.Unlock()
+}
+
+func readFileHash(path string) fileHash {
+	data, err := os.ReadFile(path)
+	return hashFile(data, err)
+}
+
+func hashFile(data []byte, err error) fileHash {
+	if err != nil || data == nil {
+		return fileHash{}
+	}
+	return sha256.Sum256(data)
+}
@@ -0,0 +1,120 @@
+package extjson
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type watchConfig struct {
+	Name    string `json:"name"`
+	Servers []struct {
+		Host string `json:"host"`
+	} `json:"servers"`
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeFile("main.json", `{name: "a", servers: @incl("servers.json")}`)
+	writeFile("servers.json", `[@incl("server.json")]`)
+	writeFile("server.json", `{host: "h1"}`)
+
+	var v watchConfig
+	w, err := Watch(filepath.Join(dir, "main.json"), &v, IncludeRoot(dir), WatchInterval(5*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	if w.Current().(*watchConfig) != &v || v.Name != "a" || v.Servers[0].Host != "h1" {
+		t.Fatalf("got %+v", w.Current())
+	}
+
+	waitChange := func() *watchConfig {
+		t.Helper()
+		select {
+		case <-w.Changes():
+			return w.Current().(*watchConfig)
+		case err := <-w.Errors():
+			t.Fatalf("unexpected error %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for change")
+		}
+		return nil
+	}
+	waitError := func() error {
+		t.Helper()
+		select {
+		case <-w.Changes():
+			t.Fatalf("unexpected change %+v", w.Current())
+		case err := <-w.Errors():
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("timeout waiting for error")
+		}
+		return nil
+	}
+
+	// A file included by an included file is watched.
+	writeFile("server.json", `{host: "h2"}`)
+	if got := waitChange(); got == &v || got.Servers[0].Host != "h2" || v.Servers[0].Host != "h1" {
+		t.Errorf("got %+v, the old value %+v", got, v)
+	}
+
+	// The old value is kept on errors.
+	writeFile("main.json", `{name: "b", servers: @incl("servers.json")`)
+	if err := waitError(); err == nil || w.Err() != err {
+		t.Errorf("got error %v, Err() = %v", err, w.Err())
+	}
+	if got := w.Current().(*watchConfig); got.Name != "a" || got.Servers[0].Host != "h2" {
+		t.Errorf("got %+v", got)
+	}
+
+	// Files of the current value are still watched after an error.
+	writeFile("main.json", `{name: "b", servers: @incl("missing.json")}`)
+	waitError()
+	writeFile("server.json", `{host: "h3"}`)
+	waitError()
+
+	// A missing file is watched until it is created.
+	writeFile("missing.json", `[{host: "h4"}]`)
+	if got := waitChange(); got.Name != "b" || got.Servers[0].Host != "h4" || w.Err() != nil {
+		t.Errorf("got %+v, %v", got, w.Err())
+	}
+
+	// Files which are no longer included are not watched.
+	writeFile("server.json", `{host: "h5"}`)
+	time.Sleep(50 * time.Millisecond)
+	select {
+	case <-w.Changes():
+		t.Errorf("unexpected change %+v", w.Current())
+	default:
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+}
+
+func TestWatch_Error(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "main.json")
+	if _, err := Watch(path, new(watchConfig)); !os.IsNotExist(err) {
+		t.Errorf("expecting not exist error, got %v", err)
+	}
+	if err := os.WriteFile(path, []byte(`{name: 1}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Watch(path, new(watchConfig)); err == nil {
+		t.Error("expecting type error")
+	}
+	if _, err := Watch(path, watchConfig{}); err == nil {
+		t.Error("expecting invalid argument error")
+	}
+}